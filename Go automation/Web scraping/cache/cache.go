@@ -0,0 +1,133 @@
+// Package cache keeps HTTP responses on disk so that pages are not
+// downloaded again and again while a spec is being worked on. Responses
+// are kept for a while and then checked with the site using their ETag or
+// Last-Modified date, and an offline mode serves only what is already
+// kept.
+package cache
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// ErrNotCached is returned in offline mode for a URL that is not cached.
+var ErrNotCached = errors.New("not in the cache")
+
+// Transport is an http.RoundTripper that keeps the responses to GET
+// requests in Dir, one file per URL.
+type Transport struct {
+	Dir string
+
+	// TTL is how long a kept response is served without asking the site.
+	// After that the site is asked whether it has changed, and answers
+	// with the page only if it has. Zero means always ask.
+	TTL time.Duration
+
+	// Offline serves only kept responses, however old, and fails with
+	// ErrNotCached for anything else.
+	Offline bool
+
+	// Base makes the requests that do reach the network. Nil means
+	// http.DefaultTransport.
+	Base http.RoundTripper
+}
+
+// RoundTrip answers req from the cache when it can and from the network
+// otherwise, keeping what the network says.
+func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	// Requests that carry their own conditions or ranges want an answer
+	// from the site, not from us.
+	if req.Method != http.MethodGet || req.Header.Get("Range") != "" ||
+		req.Header.Get("If-None-Match") != "" || req.Header.Get("If-Modified-Since") != "" {
+		if t.Offline {
+			return nil, ErrNotCached
+		}
+		return t.base().RoundTrip(req)
+	}
+
+	rawURL := req.URL.String()
+	entry := t.load(rawURL)
+	switch {
+	case t.Offline && entry == nil:
+		return nil, ErrNotCached
+	case t.Offline, entry != nil && time.Since(entry.Stored) < t.TTL:
+		return entry.response(req), nil
+	}
+
+	conditional := req
+	if entry != nil {
+		conditional = req.Clone(req.Context())
+		if etag := entry.Header.Get("ETag"); etag != "" {
+			conditional.Header.Set("If-None-Match", etag)
+		}
+		if modified := entry.Header.Get("Last-Modified"); modified != "" {
+			conditional.Header.Set("If-Modified-Since", modified)
+		}
+	}
+
+	response, err := t.base().RoundTrip(conditional)
+	if err != nil {
+		return nil, err
+	}
+
+	// The page has not changed: refresh what we have, taking any headers
+	// the site updated.
+	if response.StatusCode == http.StatusNotModified && entry != nil {
+		response.Body.Close()
+		for key, values := range response.Header {
+			entry.Header[key] = values
+		}
+		entry.Stored = time.Now()
+		if err := t.save(entry); err != nil {
+			return nil, err
+		}
+		return entry.response(req), nil
+	}
+
+	if !cacheable[response.StatusCode] || noStore(response.Header) {
+		return response, nil
+	}
+
+	body, err := io.ReadAll(response.Body)
+	response.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	entry = &Entry{
+		URL:        rawURL,
+		StatusCode: response.StatusCode,
+		Status:     response.Status,
+		Header:     response.Header,
+		Body:       body,
+		Stored:     time.Now(),
+	}
+	if err := t.save(entry); err != nil {
+		return nil, err
+	}
+	return entry.response(req), nil
+}
+
+// noStore reports whether the Cache-Control header forbids keeping the
+// response, as in "private, no-store". Directive names are not case
+// sensitive.
+func noStore(header http.Header) bool {
+	for _, value := range header.Values("Cache-Control") {
+		for _, directive := range strings.Split(value, ",") {
+			name, _, _ := strings.Cut(strings.TrimSpace(directive), "=")
+			if strings.EqualFold(strings.TrimSpace(name), "no-store") {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func (t *Transport) base() http.RoundTripper {
+	if t.Base != nil {
+		return t.Base
+	}
+	return http.DefaultTransport
+}
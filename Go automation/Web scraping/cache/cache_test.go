@@ -0,0 +1,308 @@
+package cache
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestNoStore(t *testing.T) {
+	tests := []struct {
+		values []string
+		want   bool
+	}{
+		{nil, false},
+		{[]string{"no-store"}, true},
+		{[]string{"private, no-store"}, true},
+		{[]string{"no-store, max-age=0"}, true},
+		{[]string{"  No-Store  "}, true},
+		{[]string{"max-age=60", "no-store"}, true},
+		{[]string{"no-cache, max-age=0"}, false},
+		{[]string{`private="no-store"`}, false},
+	}
+	for _, tt := range tests {
+		header := http.Header{"Cache-Control": tt.values}
+		if got := noStore(header); got != tt.want {
+			t.Errorf("noStore(%q) = %v, want %v", tt.values, got, tt.want)
+		}
+	}
+}
+
+func TestTransportHonoursNoStore(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/secret" {
+			w.Header().Set("Cache-Control", "private, no-store")
+		}
+		io.WriteString(w, "page")
+	}))
+	defer server.Close()
+
+	dir := t.TempDir()
+	online := &http.Client{Transport: &Transport{Dir: dir}}
+	offline := &http.Client{Transport: &Transport{Dir: dir, Offline: true}}
+	for _, path := range []string{"/public", "/secret"} {
+		response, err := online.Get(server.URL + path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		response.Body.Close()
+	}
+
+	response, err := offline.Get(server.URL + "/public")
+	if err != nil {
+		t.Fatalf("offline GET of a kept page: %v", err)
+	}
+	response.Body.Close()
+	if _, err := offline.Get(server.URL + "/secret"); !errors.Is(err, ErrNotCached) {
+		t.Errorf("offline GET of a no-store page = %v, want ErrNotCached", err)
+	}
+}
+
+// site is a server whose pages carry an ETag or a Last-Modified date and
+// answer conditional requests with 304 while the page is unchanged.
+type site struct {
+	*httptest.Server
+	requests   atomic.Int32
+	version    atomic.Int32 // of every page
+	conditions chan http.Header
+}
+
+func newSite(t *testing.T) *site {
+	s := &site{conditions: make(chan http.Header, 10)}
+	s.version.Store(1)
+	modified := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := s.requests.Add(1)
+		s.conditions <- http.Header{
+			"If-None-Match":     r.Header.Values("If-None-Match"),
+			"If-Modified-Since": r.Header.Values("If-Modified-Since"),
+		}
+		version := int(s.version.Load())
+		w.Header().Set("X-Served", strconv.Itoa(int(n)))
+
+		switch r.URL.Path {
+		case "/etag":
+			etag := fmt.Sprintf(`"v%d"`, version)
+			w.Header().Set("ETag", etag)
+			if r.Header.Get("If-None-Match") == etag {
+				w.WriteHeader(http.StatusNotModified)
+				return
+			}
+		case "/modified":
+			lastModified := modified.Add(time.Duration(version) * time.Hour).Format(http.TimeFormat)
+			w.Header().Set("Last-Modified", lastModified)
+			if r.Header.Get("If-Modified-Since") == lastModified {
+				w.WriteHeader(http.StatusNotModified)
+				return
+			}
+		case "/missing":
+			http.NotFound(w, r)
+			return
+		}
+		fmt.Fprintf(w, "%s version %d", r.URL.Path, version)
+	}))
+	t.Cleanup(s.Close)
+	return s
+}
+
+// condition returns the conditional headers of the last request the site
+// answered.
+func (s *site) condition(t *testing.T) http.Header {
+	t.Helper()
+	select {
+	case header := <-s.conditions:
+		return header
+	default:
+		t.Fatal("the site was not asked")
+		return nil
+	}
+}
+
+// get fetches rawURL with client and returns the status, body and header
+// of the response.
+func get(t *testing.T, client *http.Client, rawURL string) (int, string, http.Header) {
+	t.Helper()
+	response, err := client.Get(rawURL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer response.Body.Close()
+	body, err := io.ReadAll(response.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return response.StatusCode, string(body), response.Header
+}
+
+func TestRevalidate(t *testing.T) {
+	tests := []struct {
+		path, header, condition string
+	}{
+		{"/etag", "ETag", "If-None-Match"},
+		{"/modified", "Last-Modified", "If-Modified-Since"},
+	}
+	for _, tt := range tests {
+		s := newSite(t)
+		transport := &Transport{Dir: t.TempDir()}
+		client := &http.Client{Transport: transport}
+		rawURL := s.URL + tt.path
+
+		get(t, client, rawURL)
+		if got := s.condition(t); len(got[tt.condition]) != 0 {
+			t.Errorf("GET %s with nothing kept sent %s: %q", tt.path, tt.condition, got[tt.condition])
+		}
+		stored := transport.load(rawURL)
+		if stored == nil {
+			t.Fatalf("GET %s kept nothing", tt.path)
+		}
+
+		// Unchanged: the site answers 304 and the kept page is served,
+		// with the headers the site sent along and a new Stored time.
+		status, body, header := get(t, client, rawURL)
+		if got, want := s.condition(t).Get(tt.condition), stored.Header.Get(tt.header); got != want {
+			t.Errorf("GET %s sent %s %q, want %q from its %s", tt.path, tt.condition, got, want, tt.header)
+		}
+		if want := tt.path + " version 1"; status != http.StatusOK || body != want {
+			t.Errorf("GET %s when unchanged = %d %q, want 200 %q", tt.path, status, body, want)
+		}
+		if got := header.Get("X-Served"); got != "2" {
+			t.Errorf("GET %s when unchanged has X-Served %q, want the 304's \"2\"", tt.path, got)
+		}
+		refreshed := transport.load(rawURL)
+		if refreshed == nil || !refreshed.Stored.After(stored.Stored) || refreshed.Header.Get("X-Served") != "2" {
+			t.Errorf("GET %s when unchanged did not refresh the kept entry: %+v", tt.path, refreshed)
+		}
+
+		// Changed: the new page is served and kept.
+		s.version.Store(2)
+		_, body, _ = get(t, client, rawURL)
+		s.condition(t)
+		if want := tt.path + " version 2"; body != want {
+			t.Errorf("GET %s when changed = %q, want %q", tt.path, body, want)
+		}
+		if kept := transport.load(rawURL); kept == nil || string(kept.Body) != tt.path+" version 2" {
+			t.Errorf("GET %s when changed did not keep the new page", tt.path)
+		}
+	}
+}
+
+func TestTTL(t *testing.T) {
+	s := newSite(t)
+	transport := &Transport{Dir: t.TempDir(), TTL: time.Hour}
+	client := &http.Client{Transport: transport}
+	rawURL := s.URL + "/etag"
+
+	get(t, client, rawURL)
+	get(t, client, rawURL)
+	if n := s.requests.Load(); n != 1 {
+		t.Errorf("two GETs within the TTL made %d requests, want 1", n)
+	}
+
+	// Age the entry past the TTL.
+	entry := transport.load(rawURL)
+	entry.Stored = time.Now().Add(-2 * time.Hour)
+	if err := transport.save(entry); err != nil {
+		t.Fatal(err)
+	}
+	s.condition(t)
+	_, body, _ := get(t, client, rawURL)
+	if n := s.requests.Load(); n != 2 || s.condition(t).Get("If-None-Match") != `"v1"` {
+		t.Errorf("GET after the TTL made %d requests in all, want a second, conditional one", n)
+	}
+	if body != "/etag version 1" {
+		t.Errorf("GET after the TTL = %q, want the kept page", body)
+	}
+	if entry := transport.load(rawURL); time.Since(entry.Stored) > time.Minute {
+		t.Errorf("GET after the TTL left the entry stored at %s, want now", entry.Stored)
+	}
+}
+
+func TestOffline(t *testing.T) {
+	s := newSite(t)
+	dir := t.TempDir()
+	online := &http.Client{Transport: &Transport{Dir: dir}}
+	offline := &Transport{Dir: dir, Offline: true}
+
+	get(t, online, s.URL+"/etag")
+	get(t, online, s.URL+"/missing")
+	entry := offline.load(s.URL + "/etag")
+	entry.Stored = time.Now().Add(-365 * 24 * time.Hour)
+	if err := offline.save(entry); err != nil {
+		t.Fatal(err)
+	}
+	s.requests.Store(0)
+
+	client := &http.Client{Transport: offline}
+	// However old, a kept page is served, and so is a kept 404.
+	if status, body, _ := get(t, client, s.URL+"/etag"); status != http.StatusOK || body != "/etag version 1" {
+		t.Errorf("offline GET of a kept page = %d %q, want 200 \"/etag version 1\"", status, body)
+	}
+	if status, _, _ := get(t, client, s.URL+"/missing"); status != http.StatusNotFound {
+		t.Errorf("offline GET of a kept 404 = %d, want 404", status)
+	}
+
+	if _, err := client.Get(s.URL + "/modified"); !errors.Is(err, ErrNotCached) {
+		t.Errorf("offline GET of a page not kept = %v, want ErrNotCached", err)
+	}
+	req, err := http.NewRequest(http.MethodGet, s.URL+"/etag", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	req.Header.Set("If-None-Match", `"v1"`)
+	if _, err := client.Do(req); !errors.Is(err, ErrNotCached) {
+		t.Errorf("offline conditional GET = %v, want ErrNotCached", err)
+	}
+	if n := s.requests.Load(); n != 0 {
+		t.Errorf("offline GETs made %d requests, want none", n)
+	}
+}
+
+func TestEntriesAndPurge(t *testing.T) {
+	s := newSite(t)
+	transport := &Transport{Dir: filepath.Join(t.TempDir(), "cache")}
+	client := &http.Client{Transport: transport}
+
+	if entries, err := transport.Entries(); err != nil || len(entries) != 0 {
+		t.Fatalf("Entries before the cache directory exists = %v, %v, want none", entries, err)
+	}
+	if n, err := transport.Purge(); err != nil || n != 0 {
+		t.Fatalf("Purge before the cache directory exists = %d, %v, want 0", n, err)
+	}
+
+	urls := []string{s.URL + "/etag", s.URL + "/modified", s.URL + "/missing", s.URL + "/other"}
+	for _, u := range urls {
+		get(t, client, u)
+	}
+	entries, err := transport.Entries()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []string
+	for _, e := range entries {
+		got = append(got, e.URL)
+	}
+	if !slices.Equal(got, urls) {
+		t.Errorf("Entries = %q, want %q, oldest first", got, urls)
+	}
+
+	if n, err := transport.Purge(urls[0], s.URL+"/never-fetched"); err != nil || n != 1 {
+		t.Errorf("Purge of one kept URL and one other = %d, %v, want 1", n, err)
+	}
+	if transport.load(urls[0]) != nil {
+		t.Errorf("%s is still kept after Purge", urls[0])
+	}
+	if n, err := transport.Purge(); err != nil || n != 3 {
+		t.Errorf("Purge of everything = %d, %v, want 3", n, err)
+	}
+	if entries, err := transport.Entries(); err != nil || len(entries) != 0 {
+		t.Errorf("Entries after Purge = %v, %v, want none", entries, err)
+	}
+}
@@ -0,0 +1,171 @@
+package cache
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"io"
+	"io/fs"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Entry is one cached response.
+type Entry struct {
+	URL        string      `json:"url"`
+	StatusCode int         `json:"status_code"`
+	Status     string      `json:"status"`
+	Header     http.Header `json:"header"`
+	Body       []byte      `json:"body"`
+
+	// Stored is when the response was last fetched or confirmed unchanged
+	// by the site.
+	Stored time.Time `json:"stored"`
+}
+
+// cacheable is the statuses worth keeping, those RFC 9111 lets caches keep
+// without being told to. 404 is among them so that a site's missing
+// robots.txt is remembered too.
+var cacheable = map[int]bool{
+	http.StatusOK:                   true,
+	http.StatusNonAuthoritativeInfo: true,
+	http.StatusMultipleChoices:      true,
+	http.StatusMovedPermanently:     true,
+	http.StatusPermanentRedirect:    true,
+	http.StatusNotFound:             true,
+	http.StatusGone:                 true,
+}
+
+// response turns e back into a response to req.
+func (e *Entry) response(req *http.Request) *http.Response {
+	return &http.Response{
+		Status:        e.Status,
+		StatusCode:    e.StatusCode,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        e.Header.Clone(),
+		Body:          io.NopCloser(bytes.NewReader(e.Body)),
+		ContentLength: int64(len(e.Body)),
+		Request:       req,
+	}
+}
+
+// path returns the file the response for rawURL is kept in.
+func (t *Transport) path(rawURL string) string {
+	sum := sha256.Sum256([]byte(rawURL))
+	return filepath.Join(t.Dir, hex.EncodeToString(sum[:])+".json")
+}
+
+// load reads the entry for rawURL. A missing or unreadable entry is no
+// entry at all.
+func (t *Transport) load(rawURL string) *Entry {
+	data, err := os.ReadFile(t.path(rawURL))
+	if err != nil {
+		return nil
+	}
+	var e Entry
+	if err := json.Unmarshal(data, &e); err != nil || e.URL != rawURL {
+		return nil
+	}
+	return &e
+}
+
+// save writes e to disk. It writes to a temporary file first so that a
+// reader never sees half an entry.
+func (t *Transport) save(e *Entry) error {
+	data, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(t.Dir, 0o755); err != nil {
+		return err
+	}
+	file, err := os.CreateTemp(t.Dir, ".tmp-*")
+	if err != nil {
+		return err
+	}
+	if _, err := file.Write(data); err != nil {
+		file.Close()
+		os.Remove(file.Name())
+		return err
+	}
+	if err := file.Close(); err != nil {
+		os.Remove(file.Name())
+		return err
+	}
+	return os.Rename(file.Name(), t.path(e.URL))
+}
+
+// Entries returns every cached response, oldest first.
+func (t *Transport) Entries() ([]*Entry, error) {
+	files, err := t.files()
+	if err != nil {
+		return nil, err
+	}
+	var entries []*Entry
+	for _, name := range files {
+		data, err := os.ReadFile(name)
+		if err != nil {
+			return nil, err
+		}
+		var e Entry
+		if json.Unmarshal(data, &e) == nil {
+			entries = append(entries, &e)
+		}
+	}
+	sort.Slice(entries, func(i, j int) bool { return entries[i].Stored.Before(entries[j].Stored) })
+	return entries, nil
+}
+
+// Purge removes the cached responses for urls, or every cached response if
+// no urls are given, and returns how many were removed.
+func (t *Transport) Purge(urls ...string) (int, error) {
+	var files []string
+	if len(urls) == 0 {
+		var err error
+		if files, err = t.files(); err != nil {
+			return 0, err
+		}
+	}
+	for _, u := range urls {
+		files = append(files, t.path(u))
+	}
+
+	removed := 0
+	for _, name := range files {
+		err := os.Remove(name)
+		switch {
+		case err == nil:
+			removed++
+		case !errors.Is(err, fs.ErrNotExist):
+			return removed, err
+		}
+	}
+	return removed, nil
+}
+
+// files lists the entry files in the cache directory. A directory that
+// does not exist yet is an empty cache.
+func (t *Transport) files() ([]string, error) {
+	dir, err := os.ReadDir(t.Dir)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var files []string
+	for _, f := range dir {
+		if !f.IsDir() && strings.HasSuffix(f.Name(), ".json") {
+			files = append(files, filepath.Join(t.Dir, f.Name()))
+		}
+	}
+	return files, nil
+}
@@ -0,0 +1,59 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"text/tabwriter"
+	"time"
+
+	"github.com/TheInvincibleRalph/Go-automation.git/cache"
+)
+
+// defaultCacheDir is where pages are kept unless -cache-dir says otherwise
+func defaultCacheDir() string {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return ".scrape-cache"
+	}
+	return filepath.Join(dir, "go-automation-scraper")
+}
+
+// cacheCommand runs "scrape cache ls" and "scrape cache purge [url...]"
+func cacheCommand(args []string) {
+	flags := flag.NewFlagSet("cache", flag.ExitOnError)
+	dir := flags.String("cache-dir", defaultCacheDir(), "where -cache keeps pages")
+	flags.Usage = func() {
+		fmt.Fprintln(os.Stderr, "usage: scrape cache [-cache-dir dir] ls|purge [url...]")
+		flags.PrintDefaults()
+	}
+	flags.Parse(args)
+
+	transport := &cache.Transport{Dir: *dir}
+	switch flags.Arg(0) {
+	case "ls":
+		entries, err := transport.Entries()
+		if err != nil {
+			log.Fatal("Failed to read the cache: ", err)
+		}
+		//one line per page: its status, size, age and URL
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		for _, e := range entries {
+			fmt.Fprintf(w, "%d\t%d\t%s\t%s\n", e.StatusCode, len(e.Body), time.Since(e.Stored).Round(time.Second), e.URL)
+		}
+		if err := w.Flush(); err != nil {
+			log.Fatal(err)
+		}
+	case "purge":
+		removed, err := transport.Purge(flags.Args()[1:]...)
+		if err != nil {
+			log.Fatal("Failed to purge the cache: ", err)
+		}
+		fmt.Printf("removed %d pages from %s\n", removed, *dir)
+	default:
+		flags.Usage()
+		os.Exit(2)
+	}
+}
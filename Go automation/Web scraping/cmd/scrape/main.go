@@ -0,0 +1,212 @@
+// Command scrape runs the spec-driven scraper. Each site is described by a
+// YAML spec in the specs folder:
+//
+//	scrape -spec specs/jiji-cars.yaml -out cars.csv
+//	scrape -spec specs/jiji-cars.yaml -validate
+//
+// While working on a spec, -cache keeps the pages on disk so that they are
+// not downloaded on every run, and -offline uses only the kept pages. The
+// cache itself is looked after with:
+//
+//	scrape cache ls
+//	scrape cache purge [url...]
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"os/signal"
+	"time"
+
+	"github.com/TheInvincibleRalph/Go-automation.git/cache"
+	"github.com/TheInvincibleRalph/Go-automation.git/fetch"
+	"github.com/TheInvincibleRalph/Go-automation.git/output"
+	"github.com/TheInvincibleRalph/Go-automation.git/robots"
+	"github.com/TheInvincibleRalph/Go-automation.git/scraper"
+)
+
+// userAgent identifies the scraper to the sites it visits
+const userAgent = "Go-automation-scraper/1.0"
+
+func main() {
+	if len(os.Args) > 1 && os.Args[1] == "cache" {
+		cacheCommand(os.Args[2:])
+		return
+	}
+
+	specPath := flag.String("spec", "", "YAML spec describing the site to scrape")
+	out := flag.String("out", "-", "CSV file to write the items to (- for stdout)")
+	validate := flag.Bool("validate", false, "report selectors that match nothing instead of scraping")
+	timeout := flag.Duration("timeout", 30*time.Second, "how long to wait for a page")
+	concurrency := flag.Int("concurrency", 4, "most pages to fetch at once")
+	delay := flag.Duration("delay", time.Second, "time between requests to the same host")
+	burst := flag.Int("burst", 1, "requests allowed to a host at once before -delay applies")
+	maxPages := flag.Int("max-pages", 0, "most pages to visit (the spec's max_pages, or 1, if 0)")
+	retries := flag.Int("retries", fetch.DefaultRetry.Retries, "times to try a page again after a temporary failure")
+	retryBudget := flag.Duration("retry-budget", fetch.DefaultRetry.Budget, "longest a page may spend waiting to be tried again")
+	ignoreRobots := flag.Bool("ignore-robots", false, "fetch pages robots.txt disallows (each one is logged)")
+	useCache := flag.Bool("cache", false, "keep downloaded pages on disk and reuse them")
+	cacheDir := flag.String("cache-dir", defaultCacheDir(), "where -cache keeps pages")
+	cacheTTL := flag.Duration("cache-ttl", time.Hour, "how long a kept page is reused before asking the site whether it changed")
+	offline := flag.Bool("offline", false, "use only pages already in the cache, without touching the network")
+	flag.Parse()
+
+	if *specPath == "" {
+		fmt.Fprintln(os.Stderr, "usage: scrape -spec site.yaml [-out items.csv] [-validate] [-cache] [-offline]")
+		fmt.Fprintln(os.Stderr, "       scrape cache ls|purge [url...]")
+		flag.PrintDefaults()
+		os.Exit(2)
+	}
+
+	spec, err := scraper.LoadSpec(*specPath)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	retry := fetch.DefaultRetry
+	retry.Retries, retry.Budget = *retries, *retryBudget
+
+	//pages and robots.txt files both go through the cache when it is on
+	transport := http.DefaultTransport
+	if *useCache || *offline {
+		transport = &cache.Transport{Dir: *cacheDir, TTL: *cacheTTL, Offline: *offline}
+	}
+	//offline there is no site to be polite to and nothing worth retrying
+	if *offline {
+		*delay, retry.Retries = 0, 0
+	}
+
+	client := &fetch.Client{
+		HTTP:         &http.Client{Transport: transport},
+		Limiter:      fetch.NewLimiter(*delay, *burst),
+		Robots:       &robots.Checker{UserAgent: userAgent},
+		IgnoreRobots: *ignoreRobots,
+		Timeout:      *timeout,
+		UserAgent:    userAgent,
+		Retry:        retry,
+	}
+	//robots.txt files are rate limited and retried like pages
+	client.Robots.Get = client.GetRobots
+	if *ignoreRobots {
+		log.Print("WARNING: -ignore-robots is set; pages disallowed by robots.txt will be fetched anyway")
+	}
+
+	//stop cleanly on Ctrl-C, keeping the items scraped so far
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	if *validate {
+		page, err := client.Get(ctx, spec.StartURL)
+		if err != nil {
+			log.Fatal("Failed to get targeted HTML page: ", err)
+		}
+		document, err := scraper.Document(page)
+		if err != nil {
+			log.Fatal(err)
+		}
+		if !report(spec.Validate(document)) {
+			os.Exit(1)
+		}
+		return
+	}
+
+	if *maxPages == 0 {
+		*maxPages = max(spec.Pagination.MaxPages, 1)
+	}
+
+	//write the items to stdout unless a file was given
+	var dest io.Writer = os.Stdout
+	var file *os.File
+	if *out != "-" {
+		file, err = os.Create(*out)
+		if err != nil {
+			log.Fatal("Failed to create the output CSV file: ", err)
+		}
+		dest = file
+	}
+
+	writer, err := output.NewCSV(dest, spec)
+	if err != nil {
+		log.Fatal("Failed to write the CSV file: ", err)
+	}
+
+	var pages []scraper.Page
+	crawlErr := spec.Crawl(ctx, client, *maxPages, *concurrency, func(page scraper.Page, items []scraper.Item) error {
+		pages = append(pages, page)
+		//a bad page is reported and skipped, the rest of the run goes on
+		if page.Err != nil {
+			log.Printf("page %d: skipped: %v", page.Number, page.Err)
+			return nil
+		}
+		log.Printf("page %d: %d items, %d already seen (%s)", page.Number, page.Items, page.Duplicates, page.URL)
+		for _, item := range items {
+			if err := writer.Write(item, page.FetchedAt); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+
+	for _, err := range writer.BadPrices {
+		log.Print(err)
+	}
+	if err := writer.Close(); err != nil {
+		log.Fatal("Failed to write the CSV file: ", err)
+	}
+	//a write the file system deferred can still fail when the file is closed
+	if file != nil {
+		if err := file.Close(); err != nil {
+			log.Fatal("Failed to write the CSV file: ", err)
+		}
+	}
+	skipped := summarise(spec.Name, pages)
+
+	//the items scraped before a failure are kept, but the run still fails
+	if crawlErr != nil {
+		log.Fatal("Crawl stopped early: ", crawlErr)
+	}
+	if skipped > 0 && skipped == len(pages) {
+		log.Fatal("Failed to scrape any page")
+	}
+}
+
+// summarise logs how many pages were visited and what was found on them,
+// and returns how many pages had to be skipped
+func summarise(name string, pages []scraper.Page) int {
+	items, duplicates, skipped := 0, 0, 0
+	for _, page := range pages {
+		items += page.Items
+		duplicates += page.Duplicates
+		if page.Err != nil {
+			skipped++
+		}
+	}
+	log.Printf("%s: visited %d pages, scraped %d items (%d duplicates skipped)", name, len(pages), items-duplicates, duplicates)
+	if skipped > 0 {
+		log.Printf("%s: %d pages could not be scraped and were skipped", name, skipped)
+	}
+	return skipped
+}
+
+// report prints how the spec matched the page and whether every selector
+// found something
+func report(r scraper.Report) bool {
+	fmt.Printf("%s: %d items\n", r.URL, r.Items)
+	for _, f := range r.Fields {
+		fmt.Printf("  %-20s matched %d/%d, empty %d\n", f.Name, f.Matched, r.Items, f.Empty)
+	}
+
+	problems := r.Problems()
+	for _, p := range problems {
+		fmt.Println("PROBLEM:", p)
+	}
+	if len(problems) == 0 {
+		fmt.Println("All selectors matched.")
+	}
+	return len(problems) == 0
+}
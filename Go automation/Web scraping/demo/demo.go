@@ -0,0 +1,106 @@
+// Package demo holds the extraction logic of the demo scrapers main.go,
+// Traversing.go and ecommerce-scraper.go. Those are separate programs in
+// one directory, so their logic lives here where it can be tested against
+// saved copies of the pages.
+package demo
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/TheInvincibleRalph/Go-automation.git/output"
+)
+
+// Car is one listing scraped by ScrapeCars.
+type Car struct {
+	Name  string `json:"name"`
+	Price string `json:"price"`
+}
+
+// Headings returns the text of every h1 element in an HTML document.
+func Headings(r io.Reader) ([]string, error) {
+	document, err := goquery.NewDocumentFromReader(r)
+	if err != nil {
+		return nil, err
+	}
+
+	var titles []string
+	document.Find("h1").Each(func(index int, element *goquery.Selection) { //Iterates over each h1 element found.
+		// Get the text of the h1 element
+		titles = append(titles, element.Text())
+	})
+	return titles, nil
+}
+
+// Traverse parses an HTML document from r and writes the relatives of some
+// of its elements to w.
+func Traverse(r io.Reader, w io.Writer) error {
+	document, err := goquery.NewDocumentFromReader(r)
+	if err != nil {
+		return err
+	}
+
+	// Find all <div> elements and their direct children
+	document.Find("div").Children().Each(func(index int, element *goquery.Selection) {
+		fmt.Fprintln(w, "Child of <div>:", element.Text())
+	})
+
+	// Find the parent of <span> elements
+	document.Find("span").Parent().Each(func(index int, element *goquery.Selection) {
+		fmt.Fprintln(w, "Parent of <span>:", element.Text())
+	})
+
+	// Find all siblings of <h1> elements
+	document.Find("h1").Siblings().Each(func(index int, element *goquery.Selection) {
+		fmt.Fprintln(w, "Sibling of <h1>:", element.Text())
+	})
+
+	// Find the next sibling of <h1> elements
+	document.Find("h1").Next().Each(func(index int, element *goquery.Selection) {
+		fmt.Fprintln(w, "Next Sibling of <h1>:", element.Text())
+	})
+
+	// Find the previous sibling of <h1> elements
+	document.Find("h1").Prev().Each(func(index int, element *goquery.Selection) {
+		fmt.Fprintln(w, "Previous Sibling of <h1>:", element.Text())
+	})
+
+	// Find all <p> elements that are descendants of <div> elements
+	document.Find("div").Find("p").Each(func(index int, element *goquery.Selection) {
+		fmt.Fprintln(w, "Descendant <p> of <div>:", element.Text())
+	})
+	return nil
+}
+
+// ScrapeCars reads a page of car listings on jiji.ng and returns the name
+// and price of each car.
+func ScrapeCars(r io.Reader) ([]Car, error) {
+	document, err := goquery.NewDocumentFromReader(r)
+	if err != nil {
+		return nil, err
+	}
+
+	//where to store the scraped data (products is a slice of Car struct)
+	var products []Car
+
+	// bookHTMLElement := document.Find("div.b-list-advert__gallery__item").First()
+	// fmt.Println(bookHTMLElement)
+
+	// bookName := bookHTMLElement.Find("div.price").Text()
+	// fmt.Println(bookName)
+
+	//retrieve name and price from each product
+	document.Find("div.b-list-advert__gallery__item").Each(func(i int, p *goquery.Selection) {
+		//scraping logic
+		product := Car{}
+		//output.Normalize drops the newlines and indentation around the text
+		product.Name = output.Normalize(p.Find("div.b-list-advert-base__data__title").Text())
+		product.Price = output.Normalize(p.Find("div.b-list-advert-base__data__price").Text())
+
+		//store the scraped items
+		products = append(products, product)
+
+	})
+	return products, nil
+}
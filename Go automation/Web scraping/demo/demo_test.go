@@ -0,0 +1,52 @@
+package demo
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/TheInvincibleRalph/Go-automation.git/fetch"
+	"github.com/TheInvincibleRalph/Go-automation.git/internal/scrapetest"
+	"github.com/TheInvincibleRalph/Go-automation.git/robots"
+)
+
+// get serves the saved page name the way the demo scrapers fetch the live
+// one, robots.txt included.
+func get(t *testing.T, name string) *bytes.Reader {
+	t.Helper()
+	server := scrapetest.NewServer(t, map[string]string{
+		"/":           name,
+		"/robots.txt": "robots.txt",
+	})
+	client := &fetch.Client{HTTP: server.Client(), Robots: &robots.Checker{HTTP: server.Client()}}
+	page, err := client.Get(context.Background(), server.URL+"/")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return bytes.NewReader(page.Body)
+}
+
+func TestHeadings(t *testing.T) {
+	titles, err := Headings(get(t, "scaler.html"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	scrapetest.Golden(t, "headings", titles)
+}
+
+func TestTraverse(t *testing.T) {
+	var out strings.Builder
+	if err := Traverse(get(t, "scaler.html"), &out); err != nil {
+		t.Fatal(err)
+	}
+	scrapetest.Golden(t, "traverse", strings.Split(strings.TrimSuffix(out.String(), "\n"), "\n"))
+}
+
+func TestScrapeCars(t *testing.T) {
+	cars, err := ScrapeCars(get(t, "jiji-cars.html"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	scrapetest.Golden(t, "cars", cars)
+}
@@ -1,31 +1,24 @@
 package main
 
 import (
-	"fmt"
-	// "io"
+	"bytes"
 	"encoding/csv"
+	"fmt"
 	"log"
-	"net/http"
 	"os"
 
-	"github.com/PuerkitoBio/goquery"
+	"github.com/TheInvincibleRalph/Go-automation.git/demo"
+	"github.com/TheInvincibleRalph/Go-automation.git/fetch"
 )
 
-// custom type to keep scraped items
-type Cars struct {
-	name, price string
-}
-
 func main() {
-	//download the targeted HTML document
-	response, err := http.Get("https://jiji.ng/cars")
+	//download the targeted HTML document, unless robots.txt disallows it,
+	//trying again after failures that may pass (a status other than 200 OK
+	//comes back as an error too)
+	page, err := fetch.Get("https://jiji.ng/cars")
 	if err != nil {
 		log.Fatal("Failed to get targeted HTML page: ", err)
 	}
-	defer response.Body.Close()
-	if response.StatusCode != 200 {
-		log.Fatalf("HTTP Error %d: %s", response.StatusCode, response.Status)
-	}
 
 	// fmt.Println(response.Body)
 
@@ -41,33 +34,12 @@ func main() {
 	// stringBody := string(byteBody)
 	// fmt.Println(stringBody)
 
-	//parse the HTML document
-	document, err := goquery.NewDocumentFromReader(response.Body)
+	//parse the HTML document and scrape the cars listed on it
+	products, err := demo.ScrapeCars(bytes.NewReader(page.Body))
 	if err != nil {
 		log.Fatal("Failed to parse the HTML document: ", err)
 	}
 
-	//where to store the scraped data (products is a slice of Product struct)
-	var products []Cars
-
-	// bookHTMLElement := document.Find("div.b-list-advert__gallery__item").First()
-	// fmt.Println(bookHTMLElement)
-
-	// bookName := bookHTMLElement.Find("div.price").Text()
-	// fmt.Println(bookName)
-
-	//retrieve name and price from each product and print it
-	document.Find("div.b-list-advert__gallery__item").Each(func(i int, p *goquery.Selection) {
-		//scraping logic
-		product := Cars{}
-		product.name = p.Find("div.b-list-advert-base__data__title").Text()
-		product.price = p.Find("div.b-list-advert-base__data__price").Text()
-
-		//store the scraped items
-		products = append(products, product)
-
-	})
-
 	fmt.Println(products)
 
 	/*
@@ -84,7 +56,6 @@ func main() {
 
 	//initialize a file writer
 	writer := csv.NewWriter(file)
-	defer writer.Flush()
 
 	//define the CSV headers
 	headers := []string{
@@ -93,18 +64,28 @@ func main() {
 	}
 
 	//write the column headers
-	writer.Write(headers)
+	if err := writer.Write(headers); err != nil {
+		log.Fatal("Failed to write the CSV headers: ", err)
+	}
 
 	//add each product to the CSV file
 	for _, product := range products {
 		//convert a Product to an array of strings
 		record := []string{
-			product.name,
-			product.name,
+			product.Name,
+			product.Price,
 		}
 
 		//write a new CSV record
-		writer.Write(record)
+		if err := writer.Write(record); err != nil {
+			log.Fatal("Failed to write a CSV record: ", err)
+		}
+	}
+
+	//flush the buffered records and check that they made it to the file
+	writer.Flush()
+	if err := writer.Error(); err != nil {
+		log.Fatal("Failed to write the CSV file: ", err)
 	}
 
 	/*
@@ -0,0 +1,215 @@
+// Package fetch downloads pages politely: robots.txt is obeyed, requests to
+// each host are rate limited, every request has a timeout, failures that
+// may pass are retried with backoff, and a pool of workers can fetch many
+// pages at once while returning them in order.
+package fetch
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/TheInvincibleRalph/Go-automation.git/robots"
+)
+
+// Page is a downloaded page.
+type Page struct {
+	// URL is where the page was finally served from, after redirects.
+	URL    *url.URL
+	Header http.Header
+	Body   []byte
+}
+
+// StatusError is returned for pages served with a status other than 200 OK.
+type StatusError struct {
+	URL    string
+	Code   int
+	Status string
+
+	// RetryAfter is how long the server asked us to wait before trying
+	// again, if it sent a Retry-After header.
+	RetryAfter time.Duration
+}
+
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("GET %s: %s", e.URL, e.Status)
+}
+
+// Client fetches pages, waiting on its limiter before every request.
+type Client struct {
+	HTTP    *http.Client
+	Limiter *Limiter
+
+	// Robots, when set, is asked about every URL first. URLs it disallows
+	// are refused with a *robots.DisallowedError, and a Crawl-delay longer
+	// than the limiter's interval slows down requests to that host.
+	Robots *robots.Checker
+
+	// IgnoreRobots fetches URLs that Robots disallows anyway, logging each
+	// one so that the override shows up in the run's log.
+	IgnoreRobots bool
+
+	// Timeout bounds each request, including reading the body. Time spent
+	// waiting on the limiter does not count. Zero means no timeout.
+	Timeout time.Duration
+
+	// UserAgent is sent with every request when set.
+	UserAgent string
+
+	// Retry says how failed requests are tried again. The zero Retry
+	// tries every request once.
+	Retry Retry
+}
+
+// Default is the Client used by Get. It obeys robots.txt, fetches one page
+// a second from each host and tries failed requests again as DefaultRetry
+// allows, robots.txt files included.
+var Default = func() *Client {
+	const userAgent = "Go-automation-scraper/1.0"
+	c := &Client{
+		HTTP:      http.DefaultClient,
+		Limiter:   NewLimiter(time.Second, 1),
+		Robots:    &robots.Checker{UserAgent: userAgent},
+		Timeout:   30 * time.Second,
+		UserAgent: userAgent,
+		Retry:     DefaultRetry,
+	}
+	c.Robots.Get = c.GetRobots
+	return c
+}()
+
+// Get is http.Get for the demo scrapers: it downloads the page at rawURL
+// with Default.
+func Get(rawURL string) (*Page, error) {
+	return Default.Get(context.Background(), rawURL)
+}
+
+// Get downloads the page at rawURL, trying again as c.Retry allows when the
+// request fails in a way that may pass. A failed request is returned as a
+// *Error; a URL robots.txt disallows as a *robots.DisallowedError.
+func (c *Client) Get(ctx context.Context, rawURL string) (*Page, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, err
+	}
+	if err := c.checkRobots(ctx, u); err != nil {
+		return nil, err
+	}
+	return c.retry(ctx, u.Host, rawURL)
+}
+
+// GetRobots downloads the robots.txt file at rawURL for a robots.Checker,
+// trying again as c.Retry allows but without asking robots.txt first. Set
+// it as the checker's Get so that the file is rate limited and retried
+// like any other page.
+func (c *Client) GetRobots(ctx context.Context, rawURL string) (int, []byte, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return 0, nil, err
+	}
+	page, err := c.retry(ctx, u.Host, rawURL)
+	var status *StatusError
+	if errors.As(err, &status) {
+		return status.Code, nil, nil
+	}
+	if err != nil {
+		return 0, nil, err
+	}
+	return http.StatusOK, page.Body, nil
+}
+
+// retry downloads the page at rawURL from host, trying again as c.Retry
+// allows.
+func (c *Client) retry(ctx context.Context, host, rawURL string) (*Page, error) {
+	var waited time.Duration
+	for attempt := 1; ; attempt++ {
+		page, err := c.get(ctx, host, rawURL)
+		if err == nil {
+			return page, nil
+		}
+		kind := Classify(err)
+
+		wait, ok := c.Retry.next(attempt, waited, kind, err)
+		if !ok || ctx.Err() != nil {
+			return nil, &Error{URL: rawURL, Kind: kind, Attempts: attempt, Err: err}
+		}
+		log.Printf("GET %s: %s, trying again in %s", rawURL, kind, wait.Round(time.Millisecond))
+
+		timer := time.NewTimer(wait)
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, &Error{URL: rawURL, Kind: kind, Attempts: attempt, Err: err}
+		}
+		waited += wait
+	}
+}
+
+// get makes one attempt at downloading the page at rawURL.
+func (c *Client) get(ctx context.Context, host, rawURL string) (*Page, error) {
+	if err := c.Limiter.Wait(ctx, host); err != nil {
+		return nil, err
+	}
+
+	if c.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, c.Timeout)
+		defer cancel()
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, rawURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	if c.UserAgent != "" {
+		req.Header.Set("User-Agent", c.UserAgent)
+	}
+
+	client := c.HTTP
+	if client == nil {
+		client = http.DefaultClient
+	}
+	response, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode != http.StatusOK {
+		return nil, &StatusError{
+			URL:        rawURL,
+			Code:       response.StatusCode,
+			Status:     response.Status,
+			RetryAfter: retryAfter(response.Header.Get("Retry-After"), time.Now()),
+		}
+	}
+	body, err := io.ReadAll(response.Body)
+	if err != nil {
+		return nil, fmt.Errorf("GET %s: %w", rawURL, err)
+	}
+	return &Page{URL: response.Request.URL, Header: response.Header, Body: body}, nil
+}
+
+// checkRobots asks Robots whether u may be fetched and applies the host's
+// Crawl-delay to the limiter.
+func (c *Client) checkRobots(ctx context.Context, u *url.URL) error {
+	if c.Robots == nil {
+		return nil
+	}
+	group, err := c.Robots.Check(ctx, u.String())
+	var disallowed *robots.DisallowedError
+	switch {
+	case errors.As(err, &disallowed) && c.IgnoreRobots:
+		log.Printf("robots.txt: fetching %s anyway, ignoring %q", u, disallowed.Rule)
+	case err != nil:
+		return err
+	}
+	c.Limiter.SetInterval(u.Host, group.CrawlDelay)
+	return nil
+}
@@ -0,0 +1,119 @@
+package fetch
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// Limiter spaces out requests to each host with a token bucket. Every host
+// gets its own bucket holding up to burst tokens, refilled at one token per
+// interval, and each request takes a token. A host can be given a longer
+// interval of its own with SetInterval.
+type Limiter struct {
+	interval time.Duration
+	burst    int
+
+	mu      sync.Mutex
+	buckets map[string]*bucket
+	now     func() time.Time
+}
+
+// bucket is the state of one host. tokens goes negative when requests have
+// reserved tokens that have not been refilled yet; they wait their turn.
+type bucket struct {
+	tokens   float64
+	last     time.Time
+	interval time.Duration
+}
+
+// NewLimiter returns a limiter allowing one request per interval to each
+// host, with bursts of up to burst requests. An interval of zero disables
+// the limit.
+func NewLimiter(interval time.Duration, burst int) *Limiter {
+	return &Limiter{
+		interval: interval,
+		burst:    max(burst, 1),
+		buckets:  map[string]*bucket{},
+		now:      time.Now,
+	}
+}
+
+// Wait blocks until a request to host is allowed or ctx is done.
+func (l *Limiter) Wait(ctx context.Context, host string) error {
+	if l == nil {
+		return ctx.Err()
+	}
+
+	delay := l.reserve(host)
+	if delay <= 0 {
+		return ctx.Err()
+	}
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		l.cancel(host)
+		return ctx.Err()
+	}
+}
+
+// reserve takes a token for host and returns how long to wait until it is
+// actually available. Taking the token up front queues waiting requests in
+// the order they arrived.
+func (l *Limiter) reserve(host string) time.Duration {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.now()
+	b := l.bucket(host, now)
+	if b.interval <= 0 {
+		return 0
+	}
+
+	b.tokens += float64(now.Sub(b.last)) / float64(b.interval)
+	b.tokens = min(b.tokens, float64(l.burst))
+	b.last = now
+
+	b.tokens--
+	if b.tokens >= 0 {
+		return 0
+	}
+	return time.Duration(-b.tokens * float64(b.interval))
+}
+
+// SetInterval slows requests to host down to one per interval, as a
+// robots.txt Crawl-delay asks. It never speeds them up past the limiter's
+// own interval.
+func (l *Limiter) SetInterval(host string, interval time.Duration) {
+	if l == nil {
+		return
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	b := l.bucket(host, l.now())
+	b.interval = max(interval, l.interval)
+}
+
+// bucket returns the bucket for host, making a full one if there is none.
+// l.mu must be held.
+func (l *Limiter) bucket(host string, now time.Time) *bucket {
+	b, ok := l.buckets[host]
+	if !ok {
+		b = &bucket{tokens: float64(l.burst), last: now, interval: l.interval}
+		l.buckets[host] = b
+	}
+	return b
+}
+
+// cancel gives back a token reserved by a request that gave up waiting.
+func (l *Limiter) cancel(host string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if b, ok := l.buckets[host]; ok {
+		b.tokens = min(b.tokens+1, float64(l.burst))
+	}
+}
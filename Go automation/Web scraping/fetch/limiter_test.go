@@ -0,0 +1,92 @@
+package fetch
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+)
+
+// clock is a fake time source for the limiter.
+type clock struct{ t time.Time }
+
+func (c *clock) now() time.Time          { return c.t }
+func (c *clock) advance(d time.Duration) { c.t = c.t.Add(d) }
+
+func newTestLimiter(interval time.Duration, burst int) (*Limiter, *clock) {
+	c := &clock{t: time.Unix(0, 0)}
+	l := NewLimiter(interval, burst)
+	l.now = c.now
+	return l, c
+}
+
+func TestLimiterReserve(t *testing.T) {
+	l, c := newTestLimiter(100*time.Millisecond, 3)
+
+	// A full bucket lets a burst through at once, then requests queue up
+	// one interval apart.
+	var delays []time.Duration
+	for i := 0; i < 5; i++ {
+		delays = append(delays, l.reserve("a.example"))
+	}
+	want := []time.Duration{0, 0, 0, 100 * time.Millisecond, 200 * time.Millisecond}
+	if fmt.Sprint(delays) != fmt.Sprint(want) {
+		t.Errorf("delays = %v, want %v", delays, want)
+	}
+
+	// Other hosts have buckets of their own.
+	if d := l.reserve("b.example"); d != 0 {
+		t.Errorf("first request to another host waits %v", d)
+	}
+
+	// The bucket refills at one token per interval and never holds more
+	// than burst.
+	c.advance(time.Hour)
+	for i := 0; i < 3; i++ {
+		if d := l.reserve("a.example"); d != 0 {
+			t.Fatalf("request %d after refilling waits %v", i+1, d)
+		}
+	}
+	if d := l.reserve("a.example"); d != 100*time.Millisecond {
+		t.Errorf("request past the burst waits %v, want 100ms", d)
+	}
+}
+
+func TestLimiterSetInterval(t *testing.T) {
+	l, _ := newTestLimiter(100*time.Millisecond, 1)
+
+	l.SetInterval("slow.example", time.Second)
+	l.SetInterval("fast.example", time.Millisecond)
+	l.reserve("slow.example")
+	l.reserve("fast.example")
+	if d := l.reserve("slow.example"); d != time.Second {
+		t.Errorf("crawl delay of 1s gives a wait of %v", d)
+	}
+	if d := l.reserve("fast.example"); d != 100*time.Millisecond {
+		t.Errorf("crawl delay below the limiter's interval gives a wait of %v, want 100ms", d)
+	}
+}
+
+func TestLimiterCancel(t *testing.T) {
+	l := NewLimiter(time.Hour, 1)
+	l.Wait(context.Background(), "a.example")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if err := l.Wait(ctx, "a.example"); err != context.DeadlineExceeded {
+		t.Fatalf("Wait = %v, want the deadline", err)
+	}
+	// The token the cancelled request reserved is given back, so the next
+	// request waits one interval rather than two.
+	if d := l.reserve("a.example"); d > time.Hour {
+		t.Errorf("after a cancelled wait the next request waits %v", d)
+	}
+}
+
+func TestNilLimiter(t *testing.T) {
+	var l *Limiter
+	if err := l.Wait(context.Background(), "a.example"); err != nil {
+		t.Errorf("Wait on a nil limiter = %v", err)
+	}
+	l.SetInterval("a.example", time.Second)
+}
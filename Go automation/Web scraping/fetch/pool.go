@@ -0,0 +1,42 @@
+package fetch
+
+import (
+	"context"
+	"sync"
+)
+
+// Result is the outcome of fetching one URL.
+type Result struct {
+	URL  string
+	Page *Page
+	Err  error
+}
+
+// All fetches urls on up to workers requests at once and returns the
+// results in the same order as urls. The client's limiter still applies,
+// so requests to a single host are spaced out however many workers there
+// are. Cancelling ctx stops the fetches still waiting; their results carry
+// the context's error.
+func (c *Client) All(ctx context.Context, urls []string, workers int) []Result {
+	results := make([]Result, len(urls))
+	jobs := make(chan int)
+
+	var wg sync.WaitGroup
+	for w := 0; w < min(max(workers, 1), len(urls)); w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				page, err := c.Get(ctx, urls[i])
+				results[i] = Result{URL: urls[i], Page: page, Err: err}
+			}
+		}()
+	}
+
+	for i := range urls {
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+	return results
+}
@@ -0,0 +1,146 @@
+package fetch
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+)
+
+// recorder is a test server that notes when each request arrives.
+type recorder struct {
+	mu    sync.Mutex
+	times []time.Time
+	*httptest.Server
+}
+
+func newRecorder(t *testing.T) *recorder {
+	r := &recorder{}
+	r.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		r.mu.Lock()
+		r.times = append(r.times, time.Now())
+		r.mu.Unlock()
+		fmt.Fprint(w, req.URL.Path)
+	}))
+	t.Cleanup(r.Close)
+	return r
+}
+
+func (r *recorder) arrivals() []time.Time {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	times := append([]time.Time(nil), r.times...)
+	sort.Slice(times, func(i, j int) bool { return times[i].Before(times[j]) })
+	return times
+}
+
+// checkRate fails if any stretch of times holds more requests than a token
+// bucket of burst tokens refilled every interval lets through. slack allows
+// for requests arriving a little earlier or later than they were let go.
+func checkRate(t *testing.T, times []time.Time, interval time.Duration, burst int, slack time.Duration) {
+	t.Helper()
+	for i := range times {
+		for j := i + 1; j < len(times); j++ {
+			allowed := burst + int((times[j].Sub(times[i])+slack)/interval)
+			if j-i+1 > allowed {
+				t.Fatalf("%d requests arrived within %v, want at most %d (burst %d, one per %v)",
+					j-i+1, times[j].Sub(times[i]), allowed, burst, interval)
+			}
+		}
+	}
+}
+
+func TestAllKeepsToRate(t *testing.T) {
+	const (
+		interval = 40 * time.Millisecond
+		burst    = 3
+		requests = 12
+	)
+	server := newRecorder(t)
+	c := &Client{Limiter: NewLimiter(interval, burst)}
+
+	var urls []string
+	for i := 0; i < requests; i++ {
+		urls = append(urls, fmt.Sprintf("%s/page/%d", server.URL, i))
+	}
+	start := time.Now()
+	results := c.All(context.Background(), urls, 8)
+	elapsed := time.Since(start)
+
+	for i, r := range results {
+		if r.Err != nil {
+			t.Fatalf("%s: %v", r.URL, r.Err)
+		}
+		if r.URL != urls[i] || string(r.Page.Body) != fmt.Sprintf("/page/%d", i) {
+			t.Errorf("result %d is %s with body %q, want results in order", i, r.URL, r.Page.Body)
+		}
+	}
+
+	times := server.arrivals()
+	if len(times) != requests {
+		t.Fatalf("server saw %d requests, want %d", len(times), requests)
+	}
+	checkRate(t, times, interval, burst, 15*time.Millisecond)
+
+	// The burst goes out at once and the rest one interval apart, however
+	// many workers there are.
+	if min := (requests - burst) * interval; elapsed < min-interval/2 {
+		t.Errorf("%d requests took %v, want at least %v", requests, elapsed, min)
+	}
+}
+
+func TestAllLimitsEachHostSeparately(t *testing.T) {
+	const interval = 50 * time.Millisecond
+	a, b := newRecorder(t), newRecorder(t)
+	c := &Client{Limiter: NewLimiter(interval, 1)}
+
+	// The servers listen on different ports, so they are different hosts
+	// to the limiter.
+	var urls []string
+	for i := 0; i < 4; i++ {
+		urls = append(urls, a.URL+"/", b.URL+"/")
+	}
+	start := time.Now()
+	for _, r := range c.All(context.Background(), urls, 4) {
+		if r.Err != nil {
+			t.Fatal(r.Err)
+		}
+	}
+	elapsed := time.Since(start)
+
+	for _, s := range []*recorder{a, b} {
+		checkRate(t, s.arrivals(), interval, 1, 15*time.Millisecond)
+	}
+	// Four requests to each host one interval apart take three intervals;
+	// limiting the hosts together would take seven.
+	if elapsed > 5*interval {
+		t.Errorf("requests to two hosts took %v, as if they shared a limit", elapsed)
+	}
+}
+
+func TestAllCancelled(t *testing.T) {
+	server := newRecorder(t)
+	c := &Client{Limiter: NewLimiter(time.Hour, 1)}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	results := c.All(ctx, []string{server.URL + "/1", server.URL + "/2", server.URL + "/3"}, 3)
+
+	var fetched, cancelled int
+	for _, r := range results {
+		switch {
+		case r.Err == nil:
+			fetched++
+		case errors.Is(r.Err, context.DeadlineExceeded):
+			cancelled++
+		}
+	}
+	if fetched != 1 || cancelled != 2 {
+		t.Errorf("%d fetched and %d cancelled, want the burst of 1 fetched and the rest cancelled", fetched, cancelled)
+	}
+}
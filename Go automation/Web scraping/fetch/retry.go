@@ -0,0 +1,175 @@
+package fetch
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand/v2"
+	"net"
+	"net/http"
+	"strconv"
+	"syscall"
+	"time"
+)
+
+// Kind is the sort of failure a request ran into, which decides whether it
+// is worth trying again.
+type Kind int
+
+const (
+	// Other is any failure that trying again will not fix, such as a
+	// cancelled request.
+	Other Kind = iota
+
+	// Network is a request that timed out or whose connection failed.
+	Network
+
+	// RateLimited is a 429 Too Many Requests.
+	RateLimited
+
+	// ServerError is a 5xx status.
+	ServerError
+
+	// ClientError is any other 4xx status, such as a missing page. These
+	// are never retried.
+	ClientError
+)
+
+func (k Kind) String() string {
+	switch k {
+	case Network:
+		return "network error"
+	case RateLimited:
+		return "rate limited"
+	case ServerError:
+		return "server error"
+	case ClientError:
+		return "client error"
+	}
+	return "error"
+}
+
+// Temporary reports whether a failure of this kind may pass if the request
+// is tried again.
+func (k Kind) Temporary() bool {
+	return k == Network || k == RateLimited || k == ServerError
+}
+
+// Classify returns the kind of failure err is. Only timeouts and failures
+// of the connection itself count as network errors: a bad certificate, an
+// unsupported scheme, a redirect loop or a host that does not exist fails
+// the same way every time.
+func Classify(err error) Kind {
+	var status *StatusError
+	var netErr net.Error
+	var opErr *net.OpError
+	var dnsErr *net.DNSError
+	switch {
+	case err == nil, errors.Is(err, context.Canceled), errors.As(err, &dnsErr) && dnsErr.IsNotFound:
+		return Other
+	case errors.As(err, &status):
+		switch {
+		case status.Code == http.StatusTooManyRequests:
+			return RateLimited
+		case status.Code >= 500:
+			return ServerError
+		case status.Code >= 400:
+			return ClientError
+		}
+		return Other
+	case errors.Is(err, context.DeadlineExceeded), errors.As(err, &netErr) && netErr.Timeout(),
+		errors.Is(err, syscall.ECONNRESET), errors.Is(err, syscall.ECONNREFUSED),
+		errors.Is(err, io.EOF), errors.Is(err, io.ErrUnexpectedEOF), errors.As(err, &opErr):
+		return Network
+	}
+	return Other
+}
+
+// Error is returned by Client.Get when a request has failed for good.
+type Error struct {
+	URL      string
+	Kind     Kind
+	Attempts int
+
+	// Err is the failure of the last attempt.
+	Err error
+}
+
+func (e *Error) Error() string {
+	if e.Attempts == 1 {
+		return e.Err.Error()
+	}
+	return fmt.Sprintf("%v (%s, gave up after %d attempts)", e.Err, e.Kind, e.Attempts)
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// Retry says how a Client tries failed requests again. Only temporary
+// failures are retried, waiting longer after each one: Base, then twice
+// that, and so on up to Max, with up to half of each wait taken off at
+// random so that workers that failed together do not try again together.
+// A server asking for a longer wait with Retry-After gets it.
+type Retry struct {
+	// Retries is how many times a request may be tried again.
+	Retries int
+
+	Base time.Duration
+	Max  time.Duration
+
+	// Budget caps the total time one request may spend waiting to be
+	// tried again. Zero means no cap.
+	Budget time.Duration
+}
+
+// DefaultRetry is a Retry suited to scraping a site that sometimes has a
+// bad moment.
+var DefaultRetry = Retry{
+	Retries: 3,
+	Base:    time.Second,
+	Max:     30 * time.Second,
+	Budget:  2 * time.Minute,
+}
+
+// next returns how long to wait before trying again after a failed attempt,
+// or false if the request should not be tried again. waited is how long
+// the request has waited so far.
+func (r Retry) next(attempt int, waited time.Duration, kind Kind, err error) (time.Duration, bool) {
+	if !kind.Temporary() || attempt > r.Retries {
+		return 0, false
+	}
+
+	wait := r.Base << (attempt - 1)
+	if r.Max > 0 && (wait > r.Max || wait <= 0) {
+		wait = r.Max
+	}
+	if wait > 0 {
+		wait -= rand.N(wait/2 + 1)
+	}
+
+	var status *StatusError
+	if errors.As(err, &status) {
+		wait = max(wait, status.RetryAfter)
+	}
+	if r.Budget > 0 && waited+wait > r.Budget {
+		return 0, false
+	}
+	return wait, true
+}
+
+// retryAfter reads a Retry-After header, which holds either a number of
+// seconds or a date.
+func retryAfter(value string, now time.Time) time.Duration {
+	if value == "" {
+		return 0
+	}
+	if seconds, err := strconv.Atoi(value); err == nil {
+		return max(time.Duration(seconds)*time.Second, 0)
+	}
+	if t, err := http.ParseTime(value); err == nil {
+		return max(t.Sub(now), 0)
+	}
+	return 0
+}
@@ -0,0 +1,222 @@
+package fetch
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"sync/atomic"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/TheInvincibleRalph/Go-automation.git/robots"
+)
+
+// requestError returns the error http.Client gives for a request to url.
+func requestError(t *testing.T, client *http.Client, url string) error {
+	t.Helper()
+	response, err := client.Get(url)
+	if err == nil {
+		response.Body.Close()
+		t.Fatalf("GET %s succeeded", url)
+	}
+	return err
+}
+
+// newTLSServer starts a TLS server with a certificate clients do not trust,
+// keeping quiet about the handshakes that fail because of it.
+func newTLSServer(t *testing.T, handler http.Handler) *httptest.Server {
+	server := httptest.NewUnstartedServer(handler)
+	server.Config.ErrorLog = log.New(io.Discard, "", 0)
+	server.StartTLS()
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestClassify(t *testing.T) {
+	slow := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(200 * time.Millisecond)
+	}))
+	defer slow.Close()
+	loop := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, r.URL.Path, http.StatusFound)
+	}))
+	defer loop.Close()
+	untrusted := newTLSServer(t, http.NotFoundHandler())
+
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	closed := "http://" + l.Addr().String()
+	l.Close()
+
+	tests := []struct {
+		name string
+		err  error
+		want Kind
+	}{
+		{"nil", nil, Other},
+		{"cancelled", context.Canceled, Other},
+		{"deadline", fmt.Errorf("GET: %w", context.DeadlineExceeded), Network},
+		{"client timeout", requestError(t, &http.Client{Timeout: 20 * time.Millisecond}, slow.URL), Network},
+		{"connection refused", requestError(t, http.DefaultClient, closed), Network},
+		{"connection reset", &net.OpError{Op: "read", Net: "tcp", Err: os.NewSyscallError("read", syscall.ECONNRESET)}, Network},
+		{"bare reset", syscall.ECONNRESET, Network},
+		{"bad certificate", requestError(t, http.DefaultClient, untrusted.URL), Other},
+		{"unsupported scheme", requestError(t, http.DefaultClient, "ftp://example.com/"), Other},
+		{"redirect loop", requestError(t, http.DefaultClient, loop.URL), Other},
+		{"no such host", &net.OpError{Op: "dial", Net: "tcp", Err: &net.DNSError{Err: "no such host", Name: "nowhere.invalid", IsNotFound: true}}, Other},
+		{"too many requests", &StatusError{Code: 429}, RateLimited},
+		{"server error", &StatusError{Code: 503}, ServerError},
+		{"not found", &StatusError{Code: 404}, ClientError},
+		{"other status", &StatusError{Code: 304}, Other},
+		{"wrapped", &Error{Err: &StatusError{Code: 502}}, ServerError},
+		{"anything else", errors.New("boom"), Other},
+	}
+	for _, tt := range tests {
+		if got := Classify(tt.err); got != tt.want {
+			t.Errorf("Classify(%s: %v) = %s, want %s", tt.name, tt.err, got, tt.want)
+		}
+	}
+}
+
+func TestRetryAfter(t *testing.T) {
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		value string
+		want  time.Duration
+	}{
+		{"", 0},
+		{"120", 2 * time.Minute},
+		{"-5", 0},
+		{"Mon, 01 Jan 2024 12:00:30 GMT", 30 * time.Second},
+		{"Mon, 01 Jan 2024 11:00:00 GMT", 0},
+		{"soon", 0},
+	}
+	for _, tt := range tests {
+		if got := retryAfter(tt.value, now); got != tt.want {
+			t.Errorf("retryAfter(%q) = %v, want %v", tt.value, got, tt.want)
+		}
+	}
+}
+
+func TestRetryNext(t *testing.T) {
+	r := Retry{Retries: 3, Base: 100 * time.Millisecond, Max: 300 * time.Millisecond, Budget: time.Second}
+	temporary := &StatusError{Code: 503}
+
+	for attempt, max := range []time.Duration{100, 200, 300} {
+		wait, ok := r.next(attempt+1, 0, ServerError, temporary)
+		if max *= time.Millisecond; !ok || wait < max/2 || wait > max {
+			t.Errorf("wait after attempt %d = %v, %t, want between %v and %v", attempt+1, wait, ok, max/2, max)
+		}
+	}
+	if _, ok := r.next(4, 0, ServerError, temporary); ok {
+		t.Error("retried after running out of retries")
+	}
+	if _, ok := r.next(1, 0, ClientError, &StatusError{Code: 404}); ok {
+		t.Error("retried a client error")
+	}
+	if _, ok := r.next(1, 960*time.Millisecond, ServerError, temporary); ok {
+		t.Error("retried past the budget")
+	}
+	if wait, _ := r.next(1, 0, RateLimited, &StatusError{Code: 429, RetryAfter: 500 * time.Millisecond}); wait != 500*time.Millisecond {
+		t.Errorf("wait with Retry-After of 500ms = %v", wait)
+	}
+}
+
+func TestGetRetries(t *testing.T) {
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch n := requests.Add(1); {
+		case r.URL.Path == "/missing":
+			http.NotFound(w, r)
+		case n <= 2:
+			http.Error(w, "try later", http.StatusServiceUnavailable)
+		default:
+			fmt.Fprint(w, "ok")
+		}
+	}))
+	defer server.Close()
+	c := &Client{Retry: Retry{Retries: 3, Base: time.Millisecond}}
+
+	page, err := c.Get(context.Background(), server.URL+"/flaky")
+	if err != nil || string(page.Body) != "ok" || requests.Load() != 3 {
+		t.Fatalf("Get = %v after %d requests, want ok on the third", err, requests.Load())
+	}
+
+	requests.Store(100)
+	_, err = c.Get(context.Background(), server.URL+"/missing")
+	var fetchErr *Error
+	if !errors.As(err, &fetchErr) || fetchErr.Kind != ClientError || fetchErr.Attempts != 1 {
+		t.Errorf("Get of a missing page = %v, want a client error after one attempt", err)
+	}
+}
+
+func TestGetDoesNotRetryBadCertificate(t *testing.T) {
+	var requests atomic.Int32
+	server := newTLSServer(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+	}))
+	c := &Client{Retry: Retry{Retries: 3, Base: time.Millisecond}}
+
+	_, err := c.Get(context.Background(), server.URL)
+	var fetchErr *Error
+	if !errors.As(err, &fetchErr) || fetchErr.Kind != Other || fetchErr.Attempts != 1 {
+		t.Errorf("Get with an untrusted certificate = %v, want one attempt", err)
+	}
+}
+
+func TestGetRetriesRobots(t *testing.T) {
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path != "/robots.txt":
+			fmt.Fprint(w, "ok")
+		case requests.Add(1) <= 2:
+			http.Error(w, "try later", http.StatusServiceUnavailable)
+		default:
+			fmt.Fprint(w, "User-agent: *\nDisallow: /private\n")
+		}
+	}))
+	defer server.Close()
+	c := &Client{Robots: &robots.Checker{}, Retry: Retry{Retries: 3, Base: time.Millisecond}}
+	c.Robots.Get = c.GetRobots
+
+	page, err := c.Get(context.Background(), server.URL+"/cars")
+	if err != nil || string(page.Body) != "ok" || requests.Load() != 3 {
+		t.Fatalf("Get = %v after %d requests for robots.txt, want ok after the third", err, requests.Load())
+	}
+	var disallowed *robots.DisallowedError
+	if _, err := c.Get(context.Background(), server.URL+"/private"); !errors.As(err, &disallowed) {
+		t.Errorf("Get(/private) = %v, want a *robots.DisallowedError", err)
+	}
+}
+
+func TestGetRobotsUnreachable(t *testing.T) {
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/robots.txt" {
+			requests.Add(1)
+			http.Error(w, "down", http.StatusBadGateway)
+			return
+		}
+		fmt.Fprint(w, "ok")
+	}))
+	defer server.Close()
+	c := &Client{Robots: &robots.Checker{}, Retry: Retry{Retries: 2, Base: time.Millisecond}}
+	c.Robots.Get = c.GetRobots
+
+	_, err := c.Get(context.Background(), server.URL+"/cars")
+	var unreachable *robots.UnreachableError
+	var disallowed *robots.DisallowedError
+	if !errors.As(err, &unreachable) || errors.As(err, &disallowed) || requests.Load() != 3 {
+		t.Errorf("Get with robots.txt failing = %v after %d requests, want an *UnreachableError after three", err, requests.Load())
+	}
+}
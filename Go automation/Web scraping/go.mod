@@ -2,9 +2,10 @@ module github.com/TheInvincibleRalph/Go-automation.git
 
 go 1.22.1
 
-require github.com/PuerkitoBio/goquery v1.9.2
-
 require (
-	github.com/andybalholm/cascadia v1.3.2 // indirect
-	golang.org/x/net v0.24.0 // indirect
+	github.com/PuerkitoBio/goquery v1.9.2
+	github.com/andybalholm/cascadia v1.3.2
+	gopkg.in/yaml.v3 v3.0.1
 )
+
+require golang.org/x/net v0.24.0 // indirect
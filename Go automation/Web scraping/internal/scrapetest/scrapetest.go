@@ -0,0 +1,75 @@
+// Package scrapetest serves saved pages to scrapers under test and compares
+// what they extract with golden files.
+//
+// Pages and golden files live in the testdata directory of the package
+// being tested. Run its tests with -update to rewrite the golden files from
+// the current output:
+//
+//	go test ./scraper -update
+package scrapetest
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+var update = flag.Bool("update", false, "rewrite the golden files in testdata")
+
+// NewServer starts a server that answers each request URI in pages, such as
+// "/cars?page=2", with the file of that name in testdata. Anything else is
+// not found. The server is closed when the test ends.
+func NewServer(t *testing.T, pages map[string]string) *httptest.Server {
+	t.Helper()
+	for _, name := range pages {
+		if _, err := os.Stat(filepath.Join("testdata", name)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		name, ok := pages[r.URL.RequestURI()]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		http.ServeFile(w, r, filepath.Join("testdata", name))
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+// Golden compares got, as indented JSON, with testdata/name.golden.json, or
+// writes it there when the tests are run with -update.
+func Golden(t *testing.T, name string, got any) {
+	t.Helper()
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	encoder.SetEscapeHTML(false)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(got); err != nil {
+		t.Fatal(err)
+	}
+	data := buf.Bytes()
+
+	path := filepath.Join("testdata", name+".golden.json")
+	if *update {
+		if err := os.WriteFile(path, data, 0o644); err != nil {
+			t.Fatal(err)
+		}
+		return
+	}
+
+	want, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("%v (run with -update to create it)", err)
+	}
+	if !bytes.Equal(data, want) {
+		t.Errorf("output differs from %s (run with -update if the change is intended)\ngot:\n%s\nwant:\n%s", path, data, want)
+	}
+}
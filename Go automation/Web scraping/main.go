@@ -1,34 +1,30 @@
 package main
 
 import (
+	"bytes"
 	"fmt"
 	"log"
-	"net/http"
 
-	"github.com/PuerkitoBio/goquery"
+	"github.com/TheInvincibleRalph/Go-automation.git/demo"
+	"github.com/TheInvincibleRalph/Go-automation.git/fetch"
 )
 
 func main() {
-	// Step 1: Make an HTTP request
+	// Step 1: Make an HTTP request (fetch.Get checks robots.txt first and
+	// tries again if the site has a bad moment)
 
-	response, err := http.Get("https://scaler.com")
+	page, err := fetch.Get("https://scaler.com")
 	if err != nil {
 		log.Fatal(err)
 	}
-	defer response.Body.Close()
 
-	// Step 2: Parse the HTML
+	// Step 2 and 3: Parse the HTML, then find and extract the data
 
-	document, err := goquery.NewDocumentFromReader(response.Body)
+	titles, err := demo.Headings(bytes.NewReader(page.Body))
 	if err != nil {
 		log.Fatal("Error loading HTTP response body. ", err)
 	}
-
-	// Step 3: Find and extract the data
-
-	document.Find("h1").Each(func(index int, element *goquery.Selection) { //Iterates over each h1 element found.
-		// Get the text of the h1 element
-		heading := element.Text()
+	for _, heading := range titles {
 		fmt.Println("Heading:", heading)
-	})
+	}
 }
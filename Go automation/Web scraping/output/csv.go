@@ -0,0 +1,89 @@
+// Package output writes scraped items out as CSV with clean values and a
+// stable column order.
+package output
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/TheInvincibleRalph/Go-automation.git/price"
+	"github.com/TheInvincibleRalph/Go-automation.git/scraper"
+)
+
+// Normalize trims a value and collapses every run of whitespace inside it,
+// newlines and non-breaking spaces included, to a single space.
+func Normalize(value string) string {
+	return strings.Join(strings.Fields(value), " ")
+}
+
+// CSV writes one row per item. The columns are always the listing ID and
+// URL, then the spec's columns in order, then the time the item was
+// scraped. A price column is followed by its minimum, maximum and
+// currency, so a price of "₦ 2.5M – 3M" gives 2500000.00, 3000000.00 and
+// NGN. The numbers are left empty when the price cannot be parsed.
+type CSV struct {
+	writer  *csv.Writer
+	spec    *scraper.Spec
+	columns []scraper.Field
+
+	// BadPrices collects the prices that could not be parsed.
+	BadPrices []error
+}
+
+// NewCSV writes the header row and returns a writer for the items.
+func NewCSV(w io.Writer, spec *scraper.Spec) (*CSV, error) {
+	c := &CSV{writer: csv.NewWriter(w), spec: spec}
+
+	// The ID and URL fields already have columns of their own.
+	header := []string{"id", "url"}
+	for _, column := range spec.Columns {
+		if column == spec.IDField || column == spec.URLField {
+			continue
+		}
+		f, _ := spec.Field(column)
+		c.columns = append(c.columns, f)
+
+		header = append(header, f.Name)
+		if f.IsPrice() {
+			header = append(header, f.Name+"_min", f.Name+"_max", f.Name+"_currency")
+		}
+	}
+	header = append(header, "scraped_at")
+	if err := c.writer.Write(header); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// Write adds a row for item.
+func (c *CSV) Write(item scraper.Item, scrapedAt time.Time) error {
+	id := Normalize(item[c.spec.IDField])
+	record := []string{id, Normalize(item[c.spec.URLField])}
+	for _, f := range c.columns {
+		value := Normalize(item[f.Name])
+		record = append(record, value)
+		if !f.IsPrice() {
+			continue
+		}
+
+		p, err := price.Parse(value, f.Currency)
+		if err != nil {
+			c.BadPrices = append(c.BadPrices, fmt.Errorf("listing %q: %s: %w", id, f.Name, err))
+			record = append(record, "", "", "")
+			continue
+		}
+		record = append(record, p.Min.Decimal(), p.Max.Decimal(), p.Min.Currency.Code)
+	}
+	record = append(record, scrapedAt.UTC().Format(time.RFC3339))
+	return c.writer.Write(record)
+}
+
+// Close flushes the rows still buffered and reports any error writing
+// them.
+func (c *CSV) Close() error {
+	c.writer.Flush()
+	return c.writer.Error()
+}
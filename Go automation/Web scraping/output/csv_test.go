@@ -0,0 +1,133 @@
+package output
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/TheInvincibleRalph/Go-automation.git/price"
+	"github.com/TheInvincibleRalph/Go-automation.git/scraper"
+)
+
+func TestNormalize(t *testing.T) {
+	tests := []struct {
+		value, want string
+	}{
+		{"", ""},
+		{"Toyota Camry", "Toyota Camry"},
+		{"\n            Toyota   Camry\n          ", "Toyota Camry"},
+		{"Honda\tAccord\r\n2015", "Honda Accord 2015"},
+		{"₦ 4,500,000", "₦ 4,500,000"},
+		{"    ", ""},
+	}
+	for _, tt := range tests {
+		if got := Normalize(tt.value); got != tt.want {
+			t.Errorf("Normalize(%q) = %q, want %q", tt.value, got, tt.want)
+		}
+	}
+}
+
+func loadSpec(t *testing.T) *scraper.Spec {
+	t.Helper()
+	spec, err := scraper.LoadSpec("../specs/jiji-cars.yaml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return spec
+}
+
+func TestCSV(t *testing.T) {
+	var out strings.Builder
+	c, err := NewCSV(&out, loadSpec(t))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	scrapedAt := time.Date(2024, 3, 1, 13, 30, 0, 0, time.FixedZone("WAT", 3600))
+	items := []scraper.Item{
+		{
+			"id":    "a1B2c3",
+			"url":   "https://jiji.ng/ikeja/cars/toyota-camry-2010-blue-a1B2c3.html",
+			"name":  "\n    Toyota Camry,   2010\n  ",
+			"price": "\n ₦ 4,500,000 \n",
+		},
+		{
+			"id":    " d4E5f6 ",
+			"url":   "https://jiji.ng/lekki/cars/honda-accord-d4E5f6.html",
+			"name":  "Honda Accord",
+			"price": "₦ 2.5M – 3M",
+		},
+		{
+			"id":    "j1K2l3",
+			"url":   "https://jiji.ng/ikeja/cars/mercedes-j1K2l3.html",
+			"name":  "Mercedes-Benz C300",
+			"price": "Contact for price",
+		},
+		// Fields the page did not have are left empty.
+		{"id": "m4N5o6"},
+	}
+	for _, item := range items {
+		if err := c.Write(item, scrapedAt); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := c.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	want := `id,url,name,price,price_min,price_max,price_currency,scraped_at
+a1B2c3,https://jiji.ng/ikeja/cars/toyota-camry-2010-blue-a1B2c3.html,"Toyota Camry, 2010","₦ 4,500,000",4500000.00,4500000.00,NGN,2024-03-01T12:30:00Z
+d4E5f6,https://jiji.ng/lekki/cars/honda-accord-d4E5f6.html,Honda Accord,₦ 2.5M – 3M,2500000.00,3000000.00,NGN,2024-03-01T12:30:00Z
+j1K2l3,https://jiji.ng/ikeja/cars/mercedes-j1K2l3.html,Mercedes-Benz C300,Contact for price,,,,2024-03-01T12:30:00Z
+m4N5o6,,,,,,,2024-03-01T12:30:00Z
+`
+	if out.String() != want {
+		t.Errorf("CSV wrote\n%s\nwant\n%s", out.String(), want)
+	}
+
+	if len(c.BadPrices) != 2 {
+		t.Fatalf("BadPrices = %v, want the two listings without a price", c.BadPrices)
+	}
+	if err := c.BadPrices[0]; !errors.Is(err, price.ErrUnparseable) || !strings.Contains(err.Error(), `listing "j1K2l3": price`) {
+		t.Errorf("BadPrices[0] = %v, want listing j1K2l3's price to be unparseable", err)
+	}
+}
+
+func TestCSVColumns(t *testing.T) {
+	spec := loadSpec(t)
+	// The ID and URL columns always come first, and are not repeated when
+	// the spec lists them too.
+	spec.Columns = []string{"url", "price", "name", "id"}
+
+	var out strings.Builder
+	c, err := NewCSV(&out, spec)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := c.Close(); err != nil {
+		t.Fatal(err)
+	}
+	want := "id,url,price,price_min,price_max,price_currency,name,scraped_at\n"
+	if out.String() != want {
+		t.Errorf("header = %q, want %q", out.String(), want)
+	}
+}
+
+// failingWriter fails every write, as a full disk would.
+type failingWriter struct{}
+
+func (failingWriter) Write([]byte) (int, error) { return 0, errors.New("disk full") }
+
+func TestCSVWriteErrors(t *testing.T) {
+	// Rows are buffered, so the error may only show up when they are
+	// flushed by Close.
+	c, err := NewCSV(failingWriter{}, loadSpec(t))
+	if err != nil {
+		t.Fatal(err)
+	}
+	c.Write(scraper.Item{"id": "a1B2c3", "price": "₦ 1"}, time.Now())
+	if err := c.Close(); err == nil || !strings.Contains(err.Error(), "disk full") {
+		t.Errorf("Close = %v, want the write error", err)
+	}
+}
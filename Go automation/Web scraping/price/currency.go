@@ -0,0 +1,62 @@
+package price
+
+import "strings"
+
+// Currency is an ISO 4217 currency.
+type Currency struct {
+	Code string
+
+	// Exponent is the number of minor units digits: 2 for kobo and cents,
+	// 0 for currencies like the yen that have none.
+	Exponent int
+}
+
+// currencies are the currencies prices can be given in, by code.
+var currencies = map[string]Currency{
+	"NGN": {"NGN", 2},
+	"USD": {"USD", 2},
+	"EUR": {"EUR", 2},
+	"GBP": {"GBP", 2},
+	"GHS": {"GHS", 2},
+	"KES": {"KES", 2},
+	"ZAR": {"ZAR", 2},
+	"EGP": {"EGP", 2},
+	"INR": {"INR", 2},
+	"CNY": {"CNY", 2},
+	"CAD": {"CAD", 2},
+	"AUD": {"AUD", 2},
+	"JPY": {"JPY", 0},
+	"XOF": {"XOF", 0},
+	"XAF": {"XAF", 0},
+}
+
+// symbols maps the ways currencies are written in prices to their codes.
+// Symbols shared by several currencies, like $, go to the most common one.
+var symbols = map[string]string{
+	"₦":   "NGN",
+	"N":   "NGN",
+	"$":   "USD",
+	"US$": "USD",
+	"€":   "EUR",
+	"£":   "GBP",
+	"₵":   "GHS",
+	"GH₵": "GHS",
+	"KSH": "KES",
+	"R":   "ZAR",
+	"E£":  "EGP",
+	"₹":   "INR",
+	"¥":   "JPY",
+	"CFA": "XOF",
+	"C$":  "CAD",
+	"A$":  "AUD",
+}
+
+// LookupCurrency finds a currency by its code or symbol, ignoring case.
+func LookupCurrency(s string) (Currency, bool) {
+	s = strings.ToUpper(strings.TrimSpace(s))
+	if code, ok := symbols[s]; ok {
+		s = code
+	}
+	c, ok := currencies[s]
+	return c, ok
+}
@@ -0,0 +1,258 @@
+// Package price parses prices as they are written on listing sites, like
+// "₦ 4,500,000", "$1.2k" or "₦ 2.5M – 3M", into exact amounts of money.
+package price
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"regexp"
+	"strings"
+	"unicode"
+)
+
+// ErrUnparseable is returned for text that is not a price, like
+// "Contact for price".
+var ErrUnparseable = errors.New("unparseable price")
+
+// Money is an amount in the minor units of its currency, so 4,500,000.50
+// naira is 450000050 kobo.
+type Money struct {
+	Amount   int64
+	Currency Currency
+}
+
+// Decimal formats the amount in major units, as in "4500000.50".
+func (m Money) Decimal() string {
+	if m.Currency.Exponent == 0 {
+		return fmt.Sprint(m.Amount)
+	}
+	unit := int64(math.Pow10(m.Currency.Exponent))
+	sign := ""
+	amount := m.Amount
+	if amount < 0 {
+		sign, amount = "-", -amount
+	}
+	return fmt.Sprintf("%s%d.%0*d", sign, amount/unit, m.Currency.Exponent, amount%unit)
+}
+
+func (m Money) String() string {
+	return m.Currency.Code + " " + m.Decimal()
+}
+
+// Price is a single amount or a range of amounts. For a single amount Min
+// and Max are the same.
+type Price struct {
+	Min, Max Money
+}
+
+// IsRange reports whether the price is a range.
+func (p Price) IsRange() bool {
+	return p.Min != p.Max
+}
+
+func (p Price) String() string {
+	if p.IsRange() {
+		return p.Min.String() + " - " + p.Max.Decimal()
+	}
+	return p.Min.String()
+}
+
+// rangeSeparator splits "2.5M – 3M" and "2.5M to 3M" into two amounts.
+var rangeSeparator = regexp.MustCompile(`\s*(?:–|—|~|-|\bto\b)\s*`)
+
+// Parse reads a price. The currency can be a symbol or a code, before or
+// after the amount; defaultCurrency is used when the text has none, and may
+// be empty to require one. Amounts may use "," or "." for thousands or
+// decimals and end in k, M or bn. In a range, the currency and the
+// magnitude written on one side apply to both, so "₦ 2.5 – 3M" is 2.5 to 3
+// million naira.
+func Parse(s, defaultCurrency string) (Price, error) {
+	s = strings.TrimSpace(strings.Map(func(r rune) rune {
+		if unicode.IsSpace(r) {
+			return ' '
+		}
+		return r
+	}, s))
+
+	sides := rangeSeparator.Split(s, -1)
+	if len(sides) > 2 {
+		return Price{}, fmt.Errorf("%w: %q", ErrUnparseable, s)
+	}
+
+	var amounts []amount
+	for _, side := range sides {
+		a, err := parseAmount(side)
+		if err != nil {
+			return Price{}, fmt.Errorf("%w: %q", err, s)
+		}
+		amounts = append(amounts, a)
+	}
+
+	// Share the currency and magnitude across a range.
+	low, high := &amounts[0], &amounts[len(amounts)-1]
+	if low.currency == "" {
+		low.currency = high.currency
+	}
+	if high.currency == "" {
+		high.currency = low.currency
+	}
+	if low.currency != high.currency {
+		return Price{}, fmt.Errorf("%w: %q mixes currencies", ErrUnparseable, s)
+	}
+	if low.magnitude == nil {
+		low.magnitude = high.magnitude
+	}
+	if high.magnitude == nil {
+		high.magnitude = low.magnitude
+	}
+
+	code := low.currency
+	if code == "" {
+		code = defaultCurrency
+	}
+	currency, ok := LookupCurrency(code)
+	if !ok {
+		if code == "" {
+			return Price{}, fmt.Errorf("%w: %q has no currency", ErrUnparseable, s)
+		}
+		return Price{}, fmt.Errorf("%w: unknown currency %q in %q", ErrUnparseable, code, s)
+	}
+
+	lo, err := low.money(currency)
+	if err != nil {
+		return Price{}, fmt.Errorf("%w: %q", err, s)
+	}
+	hi, err := high.money(currency)
+	if err != nil {
+		return Price{}, fmt.Errorf("%w: %q", err, s)
+	}
+	if hi.Amount < lo.Amount {
+		return Price{}, fmt.Errorf("%w: %q is a range that runs backwards", ErrUnparseable, s)
+	}
+	return Price{Min: lo, Max: hi}, nil
+}
+
+// amount is one side of a price before it is converted to money. The
+// digits are only read once the magnitude is known, since it can come from
+// the other side of a range and changes how they are read.
+type amount struct {
+	digits    string
+	magnitude *big.Rat
+	currency  string
+}
+
+// magnitudes are the words and letters that scale an amount.
+var magnitudes = map[string]int64{
+	"k": 1e3, "thousand": 1e3,
+	"m": 1e6, "mn": 1e6, "mil": 1e6, "million": 1e6,
+	"b": 1e9, "bn": 1e9, "billion": 1e9,
+}
+
+// amountPattern splits an amount into the text before the number, the
+// number and the text after it.
+var amountPattern = regexp.MustCompile(`^([^0-9]*?)\s*([0-9](?:[0-9.,' ]*[0-9])?)\s*(.*)$`)
+
+func parseAmount(s string) (amount, error) {
+	m := amountPattern.FindStringSubmatch(s)
+	if m == nil {
+		return amount{}, ErrUnparseable
+	}
+	before, digits, after := m[1], m[2], m[3]
+	var a amount
+
+	// After the number comes an optional magnitude and then, if there was
+	// none before it, the currency: "2.5M", "3 million NGN", "4500 €".
+	words := strings.Fields(after)
+	if len(words) > 0 {
+		if scale, ok := magnitudes[strings.ToLower(words[0])]; ok {
+			a.magnitude = new(big.Rat).SetInt64(scale)
+			words = words[1:]
+		}
+	}
+
+	a.digits = digits
+	if len(words) > 1 {
+		return amount{}, ErrUnparseable
+	}
+
+	switch {
+	case before != "" && len(words) > 0:
+		return amount{}, ErrUnparseable
+	case before != "":
+		a.currency = strings.TrimSpace(before)
+	case len(words) > 0:
+		a.currency = words[0]
+	}
+	return a, nil
+}
+
+// parseNumber reads digits with thousands separators and at most one
+// decimal point, which may be written as a comma. A lone separator followed
+// by exactly three digits is taken as a thousands separator, as in "4,500",
+// unless a magnitude follows, as in "2.500M".
+func parseNumber(s string, scaled bool) (*big.Rat, error) {
+	s = strings.NewReplacer(" ", "", "'", "").Replace(s)
+
+	decimal := byte(0)
+	commas, dots := strings.Count(s, ","), strings.Count(s, ".")
+	switch {
+	case commas > 0 && dots > 0:
+		// Whichever comes last is the decimal point: "4,500.50" or
+		// "4.500,50".
+		if strings.LastIndex(s, ",") > strings.LastIndex(s, ".") {
+			decimal = ','
+		} else {
+			decimal = '.'
+		}
+	case commas == 1 || dots == 1:
+		sep := byte(',')
+		if dots == 1 {
+			sep = '.'
+		}
+		if len(s)-strings.IndexByte(s, sep)-1 != 3 || scaled {
+			decimal = sep
+		}
+	}
+
+	var b strings.Builder
+	seenDecimal := false
+	for i := 0; i < len(s); i++ {
+		switch c := s[i]; {
+		case c >= '0' && c <= '9':
+			b.WriteByte(c)
+		case c == decimal && !seenDecimal:
+			b.WriteByte('.')
+			seenDecimal = true
+		case c == decimal:
+			return nil, ErrUnparseable
+		}
+	}
+
+	number, ok := new(big.Rat).SetString(b.String())
+	if !ok {
+		return nil, ErrUnparseable
+	}
+	return number, nil
+}
+
+// money converts the amount to minor units, rounding half away from zero.
+func (a amount) money(currency Currency) (Money, error) {
+	minor, err := parseNumber(a.digits, a.magnitude != nil)
+	if err != nil {
+		return Money{}, err
+	}
+	if a.magnitude != nil {
+		minor.Mul(minor, a.magnitude)
+	}
+	minor.Mul(minor, new(big.Rat).SetInt64(int64(math.Pow10(currency.Exponent))))
+
+	// Round by adding a half and truncating, since amounts are positive.
+	minor.Add(minor, big.NewRat(1, 2))
+	units := new(big.Int).Quo(minor.Num(), minor.Denom())
+	if !units.IsInt64() {
+		return Money{}, fmt.Errorf("%w: amount too large", ErrUnparseable)
+	}
+	return Money{Amount: units.Int64(), Currency: currency}, nil
+}
@@ -0,0 +1,84 @@
+package price
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		text, currency string
+		want           string
+	}{
+		{"₦ 4,500,000", "", "NGN 4500000.00"},
+		{"₦4,500,000.50", "", "NGN 4500000.50"},
+		{"$1.2k", "", "USD 1200.00"},
+		{"4.500,50 €", "", "EUR 4500.50"},
+		{"3 million NGN", "", "NGN 3000000.00"},
+		{"¥ 12,000", "", "JPY 12000"},
+		{"1,250", "NGN", "NGN 1250.00"},
+		{"1,25", "EUR", "EUR 1.25"},
+		{"2.500M", "NGN", "NGN 2500000.00"},
+		{"$0.99", "", "USD 0.99"},
+
+		// Ranges share their currency and magnitude.
+		{"₦ 2.5M – 3M", "", "NGN 2500000.00 - 3000000.00"},
+		{"₦ 2.5 – 3M", "", "NGN 2500000.00 - 3000000.00"},
+		{"₦ 2.5M - 3", "", "NGN 2500000.00 - 3000000.00"},
+		{"₦ 2.5M - 3.500", "", "NGN 2500000.00 - 3500000.00"},
+		{"$500k to 1.2M", "", "USD 500000.00 - 1200000.00"},
+		{"100 - 200 USD", "", "USD 100.00 - 200.00"},
+		{"$5 - $5", "", "USD 5.00"},
+	}
+	for _, tt := range tests {
+		p, err := Parse(tt.text, tt.currency)
+		if err != nil {
+			t.Errorf("Parse(%q, %q): %v", tt.text, tt.currency, err)
+			continue
+		}
+		if got := p.String(); got != tt.want {
+			t.Errorf("Parse(%q, %q) = %s, want %s", tt.text, tt.currency, got, tt.want)
+		}
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	tests := []struct {
+		text, currency string
+	}{
+		{"Contact for price", "NGN"},
+		{"", "NGN"},
+		{"4,500", ""},
+		{"4,500 XYZ", ""},
+		{"$3M - 2M", ""},
+		{"$1 - €2", ""},
+		{"1 - 2 - 3", "USD"},
+		{"1.2.3,4,5", "USD"},
+		{"$ 4500 USD", ""},
+		{"99999999999999999999", "USD"},
+	}
+	for _, tt := range tests {
+		if p, err := Parse(tt.text, tt.currency); !errors.Is(err, ErrUnparseable) {
+			t.Errorf("Parse(%q, %q) = %v, %v, want ErrUnparseable", tt.text, tt.currency, p, err)
+		}
+	}
+}
+
+func TestDecimal(t *testing.T) {
+	naira, _ := LookupCurrency("₦")
+	yen, _ := LookupCurrency("jpy")
+	tests := []struct {
+		m    Money
+		want string
+	}{
+		{Money{450000050, naira}, "4500000.50"},
+		{Money{5, naira}, "0.05"},
+		{Money{-150, naira}, "-1.50"},
+		{Money{12000, yen}, "12000"},
+	}
+	for _, tt := range tests {
+		if got := tt.m.Decimal(); got != tt.want {
+			t.Errorf("%+v.Decimal() = %q, want %q", tt.m, got, tt.want)
+		}
+	}
+}
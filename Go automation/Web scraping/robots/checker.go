@@ -0,0 +1,236 @@
+package robots
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+
+	"github.com/TheInvincibleRalph/Go-automation.git/cache"
+)
+
+// DefaultTTL is how long a Checker keeps a robots.txt file before fetching
+// it again. RFC 9309 asks crawlers not to cache it for more than a day.
+const DefaultTTL = 24 * time.Hour
+
+// DefaultErrorTTL is how long a Checker remembers that a robots.txt file
+// could not be fetched before trying again.
+const DefaultErrorTTL = time.Minute
+
+// DisallowedError is returned for URLs robots.txt does not let the agent
+// fetch.
+type DisallowedError struct {
+	URL   string
+	Agent string
+	Rule  Rule
+}
+
+func (e *DisallowedError) Error() string {
+	return fmt.Sprintf("GET %s: disallowed for %s by robots.txt (%s)", e.URL, e.Agent, e.Rule)
+}
+
+// UnreachableError is returned for URLs on a host whose robots.txt could not
+// be fetched, because the host could not be reached or answered with a
+// server error. Nothing is known about what the file would allow.
+type UnreachableError struct {
+	URL string // of the robots.txt file
+	Err error
+}
+
+func (e *UnreachableError) Error() string {
+	return fmt.Sprintf("robots.txt %s unreachable: %v", e.URL, e.Err)
+}
+
+func (e *UnreachableError) Unwrap() error {
+	return e.Err
+}
+
+// Checker fetches the robots.txt file of each host it is asked about and
+// keeps it for TTL. The zero Checker is ready to use.
+//
+// A robots.txt file that is not found, or any other 4xx status, allows
+// everything. A server error or a host that cannot be reached is an
+// *UnreachableError, kept for ErrorTTL so that a passing failure does not
+// hold up the host for long. A file missing from an offline
+// cache.Transport wraps cache.ErrNotCached instead.
+type Checker struct {
+	HTTP *http.Client
+
+	// Get, when set, downloads robots.txt files in place of a single
+	// request with HTTP, such as with a client that tries failed requests
+	// again. It returns the status of the response with its body, or an
+	// error if there was no response.
+	Get func(ctx context.Context, rawURL string) (status int, body []byte, err error)
+
+	// UserAgent is sent when fetching robots.txt and picks the group of
+	// rules that applies.
+	UserAgent string
+
+	// TTL is how long a file is kept. Zero means DefaultTTL.
+	TTL time.Duration
+
+	// ErrorTTL is how long a failure to fetch a file is kept. Zero means
+	// DefaultErrorTTL.
+	ErrorTTL time.Duration
+
+	mu    sync.Mutex
+	hosts map[string]*entry
+}
+
+// entry is the cached robots.txt of one host. ready is closed once robots
+// or err is set, so that requests arriving while it is fetched wait for it
+// rather than fetching it again.
+type entry struct {
+	ready   chan struct{}
+	robots  *Robots
+	err     error
+	expires time.Time
+}
+
+// Check returns the rules robots.txt has for the checker's agent on the
+// host of rawURL, with a *DisallowedError if they do not allow rawURL. The
+// group is returned either way so that its Crawl-delay can be honoured.
+func (c *Checker) Check(ctx context.Context, rawURL string) (*Group, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, err
+	}
+	robots, err := c.Robots(ctx, u)
+	if err != nil {
+		return nil, err
+	}
+
+	group := robots.Group(c.UserAgent)
+	if ok, rule := group.Allowed(u.RequestURI()); !ok {
+		return group, &DisallowedError{URL: rawURL, Agent: c.UserAgent, Rule: rule}
+	}
+	return group, nil
+}
+
+// Robots returns the robots.txt file for the scheme, host and port of u,
+// fetching it if it is not cached or has expired. It fails if ctx is done
+// first, or with an error wrapping cache.ErrNotCached if the file is not
+// in an offline cache.
+func (c *Checker) Robots(ctx context.Context, u *url.URL) (*Robots, error) {
+	key := u.Scheme + "://" + u.Host
+
+	c.mu.Lock()
+	if c.hosts == nil {
+		c.hosts = map[string]*entry{}
+	}
+	e, ok := c.hosts[key]
+	if ok && (e.robots != nil || e.err != nil) && time.Now().After(e.expires) {
+		ok = false
+	}
+	if !ok {
+		e = &entry{ready: make(chan struct{})}
+		c.hosts[key] = e
+		go c.load(key, e)
+	}
+	c.mu.Unlock()
+
+	select {
+	case <-e.ready:
+		return e.robots, e.err
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// load fetches the robots.txt file at key into e. It runs on its own so
+// that one caller giving up does not spoil the file for the others.
+func (c *Checker) load(key string, e *entry) {
+	robots, err := c.fetch(key + "/robots.txt")
+	ttl := c.TTL
+	if ttl <= 0 {
+		ttl = DefaultTTL
+	}
+	if err != nil {
+		ttl = c.ErrorTTL
+		if ttl <= 0 {
+			ttl = DefaultErrorTTL
+		}
+	}
+
+	c.mu.Lock()
+	e.robots, e.err, e.expires = robots, err, time.Now().Add(ttl)
+	c.mu.Unlock()
+	close(e.ready)
+}
+
+// fetch downloads and parses one robots.txt file. It fails with an
+// *UnreachableError if there is no file to read, or with an error wrapping
+// cache.ErrNotCached on a miss in an offline cache.
+func (c *Checker) fetch(rawURL string) (*Robots, error) {
+	status, body, err := c.get(context.Background(), rawURL)
+	if errors.Is(err, cache.ErrNotCached) {
+		return nil, fmt.Errorf("robots.txt: %w", err)
+	}
+	if err != nil {
+		return nil, &UnreachableError{URL: rawURL, Err: err}
+	}
+
+	switch {
+	case status >= 500:
+		return nil, &UnreachableError{URL: rawURL, Err: fmt.Errorf("%d %s", status, http.StatusText(status))}
+	case status >= 400:
+		return &Robots{}, nil
+	case status != http.StatusOK:
+		// Redirects have been followed already, so anything else is odd
+		// enough to be careful about.
+		return disallowAll(), nil
+	}
+
+	robots, err := Parse(bytes.NewReader(body))
+	if err != nil {
+		return disallowAll(), nil
+	}
+	return robots, nil
+}
+
+// get downloads rawURL with c.Get, or with one request if it is not set.
+// The body is only read for a 200 response.
+func (c *Checker) get(ctx context.Context, rawURL string) (int, []byte, error) {
+	if c.Get != nil {
+		return c.Get(ctx, rawURL)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, rawURL, nil)
+	if err != nil {
+		return 0, nil, err
+	}
+	if c.UserAgent != "" {
+		req.Header.Set("User-Agent", c.UserAgent)
+	}
+	response, err := c.client().Do(req)
+	if err != nil {
+		return 0, nil, err
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode != http.StatusOK {
+		return response.StatusCode, nil, nil
+	}
+	body, err := io.ReadAll(response.Body)
+	if err != nil {
+		return 0, nil, err
+	}
+	return response.StatusCode, body, nil
+}
+
+func (c *Checker) client() *http.Client {
+	if c.HTTP != nil {
+		return c.HTTP
+	}
+	return http.DefaultClient
+}
+
+// disallowAll stands in for a robots.txt file that is there but cannot be
+// made sense of.
+func disallowAll() *Robots {
+	return &Robots{groups: []*Group{{agents: []string{"*"}, rules: []Rule{{Path: "/"}}}}}
+}
@@ -0,0 +1,117 @@
+package robots
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/TheInvincibleRalph/Go-automation.git/cache"
+)
+
+func TestCheckOfflineMiss(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Errorf("offline checker reached the site for %s", r.URL)
+	}))
+	defer server.Close()
+
+	c := &Checker{HTTP: &http.Client{Transport: &cache.Transport{Dir: t.TempDir(), Offline: true}}}
+	_, err := c.Check(context.Background(), server.URL+"/cars")
+	var disallowed *DisallowedError
+	if errors.As(err, &disallowed) || !errors.Is(err, cache.ErrNotCached) {
+		t.Errorf("Check with robots.txt missing from an offline cache = %v, want ErrNotCached", err)
+	}
+}
+
+func TestCheckUnreachable(t *testing.T) {
+	server := httptest.NewServer(http.NotFoundHandler())
+	server.Close()
+
+	c := &Checker{}
+	_, err := c.Check(context.Background(), server.URL+"/cars")
+	var unreachable *UnreachableError
+	var disallowed *DisallowedError
+	if !errors.As(err, &unreachable) || errors.As(err, &disallowed) {
+		t.Errorf("Check with the host unreachable = %v, want an *UnreachableError", err)
+	}
+}
+
+func TestCheckServerError(t *testing.T) {
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if requests.Add(1) == 1 {
+			http.Error(w, "try later", http.StatusServiceUnavailable)
+			return
+		}
+		fmt.Fprint(w, "User-agent: *\nDisallow: /private\n")
+	}))
+	defer server.Close()
+	c := &Checker{ErrorTTL: 50 * time.Millisecond}
+
+	_, err := c.Check(context.Background(), server.URL+"/cars")
+	var unreachable *UnreachableError
+	if !errors.As(err, &unreachable) || unreachable.URL != server.URL+"/robots.txt" {
+		t.Fatalf("Check with robots.txt failing = %v, want an *UnreachableError for it", err)
+	}
+	// The failure is kept for ErrorTTL, not for TTL.
+	if _, err := c.Check(context.Background(), server.URL+"/cars"); !errors.As(err, &unreachable) || requests.Load() != 1 {
+		t.Errorf("Check again at once = %v after %d requests, want the failure kept", err, requests.Load())
+	}
+	time.Sleep(100 * time.Millisecond)
+	if _, err := c.Check(context.Background(), server.URL+"/cars"); err != nil || requests.Load() != 2 {
+		t.Errorf("Check after ErrorTTL = %v after %d requests, want robots.txt fetched again", err, requests.Load())
+	}
+	var disallowed *DisallowedError
+	if _, err := c.Check(context.Background(), server.URL+"/private"); !errors.As(err, &disallowed) || requests.Load() != 2 {
+		t.Errorf("Check(/private) = %v after %d requests, want a *DisallowedError from the kept file", err, requests.Load())
+	}
+}
+
+func TestCheckGet(t *testing.T) {
+	tests := []struct {
+		status int
+		body   string
+		err    error
+		want   string // "allowed", "disallowed" or "unreachable"
+	}{
+		{http.StatusOK, "User-agent: *\nDisallow: /cars\n", nil, "disallowed"},
+		{http.StatusOK, "User-agent: otherbot\nDisallow: /cars\n", nil, "allowed"},
+		{http.StatusNotFound, "", nil, "allowed"},
+		{http.StatusForbidden, "", nil, "allowed"},
+		{http.StatusInternalServerError, "", nil, "unreachable"},
+		{0, "", errors.New("connection reset"), "unreachable"},
+	}
+	for _, test := range tests {
+		var got []string
+		c := &Checker{
+			UserAgent: "mybot",
+			Get: func(ctx context.Context, rawURL string) (int, []byte, error) {
+				got = append(got, rawURL)
+				return test.status, []byte(test.body), test.err
+			},
+		}
+		_, err := c.Check(context.Background(), "https://example.com/cars?page=2")
+
+		var disallowed *DisallowedError
+		var unreachable *UnreachableError
+		result := "allowed"
+		switch {
+		case errors.As(err, &disallowed):
+			result = "disallowed"
+		case errors.As(err, &unreachable):
+			result = "unreachable"
+		case err != nil:
+			result = err.Error()
+		}
+		if result != test.want {
+			t.Errorf("Check with Get returning %d, %q, %v = %v, want %s", test.status, test.body, test.err, err, test.want)
+		}
+		if len(got) != 1 || got[0] != "https://example.com/robots.txt" {
+			t.Errorf("Check called Get with %q, want https://example.com/robots.txt once", got)
+		}
+	}
+}
@@ -0,0 +1,174 @@
+// Package robots reads robots.txt files and decides whether a crawler may
+// fetch a URL, following RFC 9309: rules are grouped by user agent, the
+// longest matching rule wins with Allow winning ties, paths may use the *
+// and $ wildcards, and Crawl-delay is honoured.
+package robots
+
+import (
+	"bufio"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// maxSize is how much of a robots.txt file is read. RFC 9309 asks crawlers
+// to read at least 500 KiB.
+const maxSize = 512 * 1024
+
+// Robots is a parsed robots.txt file.
+type Robots struct {
+	groups []*Group
+}
+
+// Group is the rules for one set of user agents.
+type Group struct {
+	agents []string
+	rules  []Rule
+
+	// CrawlDelay is how long to wait between requests, if the file asks.
+	CrawlDelay time.Duration
+}
+
+// Rule is one Allow or Disallow line.
+type Rule struct {
+	Allow bool
+	Path  string
+}
+
+func (r Rule) String() string {
+	if r.Allow {
+		return "Allow: " + r.Path
+	}
+	return "Disallow: " + r.Path
+}
+
+// Parse reads a robots.txt file. Lines it does not understand are skipped,
+// as the standard asks.
+func Parse(r io.Reader) (*Robots, error) {
+	robots := &Robots{}
+	var group *Group
+	// A run of User-agent lines starts one group; any rule ends the run.
+	inAgents := false
+
+	scanner := bufio.NewScanner(io.LimitReader(r, maxSize))
+	for scanner.Scan() {
+		line := scanner.Text()
+		if i := strings.IndexByte(line, '#'); i >= 0 {
+			line = line[:i]
+		}
+		key, value, ok := strings.Cut(line, ":")
+		if !ok {
+			continue
+		}
+		key = strings.ToLower(strings.TrimSpace(key))
+		value = strings.TrimSpace(value)
+
+		switch key {
+		case "user-agent":
+			if !inAgents {
+				group = &Group{}
+				robots.groups = append(robots.groups, group)
+				inAgents = true
+			}
+			group.agents = append(group.agents, strings.ToLower(value))
+		case "allow", "disallow":
+			inAgents = false
+			// An empty Disallow allows everything, which is the same as no
+			// rule at all.
+			if group != nil && value != "" {
+				group.rules = append(group.rules, Rule{Allow: key == "allow", Path: value})
+			}
+		case "crawl-delay":
+			inAgents = false
+			if seconds, err := strconv.ParseFloat(value, 64); err == nil && group != nil && seconds >= 0 {
+				group.CrawlDelay = time.Duration(seconds * float64(time.Second))
+			}
+		}
+	}
+	return robots, scanner.Err()
+}
+
+// Group returns the rules that apply to agent: those of every group naming
+// it, or else those of the * groups. The agent's product token is matched
+// without regard to case, so "MyBot/1.0" matches a group for "mybot".
+func (r *Robots) Group(agent string) *Group {
+	token := strings.ToLower(agent)
+	if i := strings.IndexAny(token, "/ "); i >= 0 {
+		token = token[:i]
+	}
+
+	merged := &Group{}
+	for _, want := range []string{token, "*"} {
+		found := false
+		for _, g := range r.groups {
+			for _, a := range g.agents {
+				if a == want {
+					merged.rules = append(merged.rules, g.rules...)
+					merged.CrawlDelay = max(merged.CrawlDelay, g.CrawlDelay)
+					found = true
+					break
+				}
+			}
+		}
+		// A group for the agent itself replaces the * groups, even if it
+		// has no rules.
+		if found {
+			break
+		}
+	}
+	return merged
+}
+
+// Allowed reports whether path, with its query string, may be fetched and
+// returns the rule that decided it. The zero Rule means no rule matched,
+// which allows everything.
+func (g *Group) Allowed(path string) (bool, Rule) {
+	if path == "" {
+		path = "/"
+	}
+	if path == "/robots.txt" {
+		return true, Rule{}
+	}
+
+	var best Rule
+	bestLen := -1
+	for _, rule := range g.rules {
+		if !match(rule.Path, path) {
+			continue
+		}
+		n := len(rule.Path)
+		if n > bestLen || (n == bestLen && rule.Allow && !best.Allow) {
+			best, bestLen = rule, n
+		}
+	}
+	if bestLen < 0 {
+		return true, Rule{}
+	}
+	return best.Allow, best
+}
+
+// match reports whether path starts with pattern, where * in the pattern
+// matches any run of characters and a final $ anchors it to the end.
+func match(pattern, path string) bool {
+	anchored := strings.HasSuffix(pattern, "$")
+	pattern = strings.TrimSuffix(pattern, "$")
+
+	parts := strings.Split(pattern, "*")
+	if !strings.HasPrefix(path, parts[0]) {
+		return false
+	}
+	rest := path[len(parts[0]):]
+	for i, part := range parts[1:] {
+		last := i == len(parts)-2
+		if last && anchored {
+			return strings.HasSuffix(rest, part)
+		}
+		j := strings.Index(rest, part)
+		if j < 0 {
+			return false
+		}
+		rest = rest[j+len(part):]
+	}
+	return !anchored || rest == ""
+}
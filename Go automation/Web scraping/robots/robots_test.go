@@ -0,0 +1,178 @@
+package robots
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+// parse parses a robots.txt file given as a string.
+func parse(t *testing.T, file string) *Robots {
+	t.Helper()
+	robots, err := Parse(strings.NewReader(file))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return robots
+}
+
+func TestMatch(t *testing.T) {
+	tests := []struct {
+		pattern, path string
+		want          bool
+	}{
+		{"/", "/", true},
+		{"/", "/cars", true},
+		{"/cars", "/cars", true},
+		{"/cars", "/cars/1", true},
+		{"/cars", "/carsales", true},
+		{"/cars", "/car", false},
+		{"/cars", "/Cars", false},
+		{"/cars/", "/cars", false},
+		{"/*.pdf", "/docs/manual.pdf", true},
+		{"/*.pdf", "/docs/manual.pdf?download=1", true},
+		{"/*.pdf", "/docs/manual.html", false},
+		{"/*.pdf$", "/docs/manual.pdf", true},
+		{"/*.pdf$", "/docs/manual.pdf?download=1", false},
+		{"/cars$", "/cars", true},
+		{"/cars$", "/cars/", false},
+		{"/*/edit", "/cars/1/edit", true},
+		{"/*/edit", "/edit", false},
+		{"/a*b*c", "/axxbyyc", true},
+		{"/a*b*c", "/axxcyyb", false},
+		{"*", "/anything", true},
+		{"/*$", "/anything", true},
+		{"/search?q=*&page=", "/search?q=vw&page=2", true},
+		{"/search?q=*&page=", "/search?q=vw", false},
+	}
+	for _, test := range tests {
+		if got := match(test.pattern, test.path); got != test.want {
+			t.Errorf("match(%q, %q) = %v, want %v", test.pattern, test.path, got, test.want)
+		}
+	}
+}
+
+func TestAllowed(t *testing.T) {
+	group := parse(t, `
+User-agent: *
+Disallow: /cars
+Allow: /cars/public
+Disallow: /cars/public/drafts
+Allow: /page
+Disallow: /page
+Disallow: /*.pdf$
+Allow: /docs/*.pdf$
+Disallow: /
+Allow: /$
+`).Group("mybot")
+
+	tests := []struct {
+		path    string
+		allowed bool
+		rule    Rule
+	}{
+		{"/cars", false, Rule{Path: "/cars"}},
+		{"/cars/1", false, Rule{Path: "/cars"}},
+		{"/cars/public/1", true, Rule{Allow: true, Path: "/cars/public"}},
+		{"/cars/public/drafts/1", false, Rule{Path: "/cars/public/drafts"}},
+		// Allow wins a tie, whichever comes first.
+		{"/page", true, Rule{Allow: true, Path: "/page"}},
+		{"/files/price-list.pdf", false, Rule{Path: "/*.pdf$"}},
+		{"/docs/manual.pdf", true, Rule{Allow: true, Path: "/docs/*.pdf$"}},
+		{"/", true, Rule{Allow: true, Path: "/$"}},
+		{"", true, Rule{Allow: true, Path: "/$"}},
+		{"/about", false, Rule{Path: "/"}},
+		// robots.txt itself is always allowed.
+		{"/robots.txt", true, Rule{}},
+	}
+	for _, test := range tests {
+		allowed, rule := group.Allowed(test.path)
+		if allowed != test.allowed || rule != test.rule {
+			t.Errorf("Allowed(%q) = %v, %q, want %v, %q", test.path, allowed, rule, test.allowed, test.rule)
+		}
+	}
+}
+
+func TestGroup(t *testing.T) {
+	robots := parse(t, `
+# Rules before any User-agent line belong to no group.
+Disallow: /everyone
+
+User-agent: *
+Disallow: /private # everyone but the bots below
+Crawl-delay: 2
+
+User-agent: MyBot
+user-agent: OtherBot
+Disallow: /drafts
+
+USER-AGENT: mybot
+DISALLOW: /tmp
+Crawl-delay: 0.5
+
+User-agent: EmptyBot
+Disallow:
+
+User-agent: DelayBot
+Crawl-delay: 10
+Crawl-delay: soon
+`)
+
+	tests := []struct {
+		agent string
+		paths map[string]bool
+		delay time.Duration
+	}{
+		// Both groups naming mybot are merged, and the * group is left out.
+		{"mybot", map[string]bool{"/drafts/1": false, "/tmp/1": false, "/private": true, "/everyone": true}, 500 * time.Millisecond},
+		{"MyBot/1.0", map[string]bool{"/drafts/1": false, "/tmp/1": false, "/private": true}, 500 * time.Millisecond},
+		{"MYBOT (+https://example.com)", map[string]bool{"/tmp/1": false}, 500 * time.Millisecond},
+		// A group may name more than one agent.
+		{"otherbot", map[string]bool{"/drafts/1": false, "/tmp/1": true, "/private": true}, 0},
+		// Anyone else falls back to *.
+		{"somebot", map[string]bool{"/drafts/1": true, "/private": false, "/everyone": true}, 2 * time.Second},
+		{"", map[string]bool{"/private": false}, 2 * time.Second},
+		// An empty Disallow allows everything, and replaces * all the same.
+		{"emptybot", map[string]bool{"/private": true, "/": true}, 0},
+		// A Crawl-delay that is not a number is skipped.
+		{"delaybot", map[string]bool{"/private": true}, 10 * time.Second},
+	}
+	for _, test := range tests {
+		group := robots.Group(test.agent)
+		for path, want := range test.paths {
+			if got, rule := group.Allowed(path); got != want {
+				t.Errorf("Group(%q).Allowed(%q) = %v (%q), want %v", test.agent, path, got, rule, want)
+			}
+		}
+		if group.CrawlDelay != test.delay {
+			t.Errorf("Group(%q).CrawlDelay = %s, want %s", test.agent, group.CrawlDelay, test.delay)
+		}
+	}
+}
+
+func TestGroupMergesCrawlDelay(t *testing.T) {
+	robots := parse(t, `
+User-agent: *
+Crawl-delay: 1
+
+User-agent: *
+Crawl-delay: 3
+Disallow: /tmp
+`)
+	group := robots.Group("mybot")
+	if group.CrawlDelay != 3*time.Second {
+		t.Errorf("CrawlDelay = %s, want the longest, 3s", group.CrawlDelay)
+	}
+	if ok, _ := group.Allowed("/tmp"); ok {
+		t.Error("Allowed(\"/tmp\") = true, want the rules of both * groups")
+	}
+}
+
+func TestParseEmpty(t *testing.T) {
+	for _, file := range []string{"", "\n\n", "# nothing here\n", "Sitemap: https://example.com/sitemap.xml\n", "not a robots.txt file"} {
+		group := parse(t, file).Group("mybot")
+		if ok, rule := group.Allowed("/cars"); !ok {
+			t.Errorf("Parse(%q) disallows /cars by %q, want everything allowed", file, rule)
+		}
+	}
+}
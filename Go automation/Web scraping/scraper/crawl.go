@@ -0,0 +1,185 @@
+package scraper
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/TheInvincibleRalph/Go-automation.git/fetch"
+)
+
+// Pagination says how to get from one page of listings to the next. Only
+// one of the two ways may be set.
+type Pagination struct {
+	// NextSelector matches the link to the next page, as in
+	// "a.pagination__next".
+	NextSelector string `yaml:"next_selector"`
+
+	// PageTemplate is the URL of every page after the first, with {page}
+	// standing for the page number, as in "https://jiji.ng/cars?page={page}".
+	PageTemplate string `yaml:"page_template"`
+
+	// MaxPages is how many pages to visit when the command line does not
+	// say. Zero means one page.
+	MaxPages int `yaml:"max_pages"`
+}
+
+// Page describes one page visited by Crawl.
+type Page struct {
+	Number    int
+	URL       string
+	FetchedAt time.Time
+
+	// Items counts the items on the page and Duplicates the ones among
+	// them that were already seen on an earlier page.
+	Items      int
+	Duplicates int
+
+	// Err is why the page could not be fetched or read. Such pages are
+	// skipped and have no items.
+	Err error
+}
+
+// Crawl visits up to maxPages pages, starting at the start URL, and calls
+// page with each page and the items on it that were not seen before. It
+// stops early when there is no next page, when a page has no new items, or
+// when a page after the first is not found, as happens when a page
+// template runs past the last page. It also stops if page returns an error
+// or ctx is done.
+//
+// A page that cannot be fetched or read is handed to page with its Err set
+// and skipped. With a page template the crawl goes on to the next page;
+// with next links there is no way on, so it ends there.
+//
+// Pages found through a next link have to be fetched one after another,
+// but with a page template up to workers pages are fetched at once. Pages
+// are still handed to page in order.
+//
+// Items are told apart by the ID field, or by the URL field if there is no
+// ID. Items with neither are never treated as duplicates.
+func (s *Spec) Crawl(ctx context.Context, client *fetch.Client, maxPages, workers int, page func(Page, []Item) error) error {
+	c := &crawl{spec: s, page: page, seen: map[string]bool{}}
+	workers = max(workers, 1)
+
+	if s.Pagination.PageTemplate != "" {
+		for first := 1; first <= maxPages; first += workers {
+			var urls []string
+			for number := first; number < first+workers && number <= maxPages; number++ {
+				urls = append(urls, s.pageURL(number))
+			}
+
+			for i, result := range client.All(ctx, urls, workers) {
+				more, _, err := c.visit(ctx, first+i, result)
+				if !more || err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	}
+
+	visited := map[string]bool{}
+	next := s.StartURL
+	for number := 1; number <= maxPages && next != "" && !visited[next]; number++ {
+		visited[next] = true
+
+		fetched, err := client.Get(ctx, next)
+		more, document, err := c.visit(ctx, number, fetch.Result{URL: next, Page: fetched, Err: err})
+		if !more || err != nil {
+			return err
+		}
+		next = s.nextPage(document)
+	}
+	return nil
+}
+
+// crawl is the state kept from one page to the next.
+type crawl struct {
+	spec *Spec
+	page func(Page, []Item) error
+	seen map[string]bool
+}
+
+// visit extracts the items of a fetched page and hands on the ones not seen
+// before. It reports whether the crawl should go on to the next page; the
+// document is nil if the page was skipped.
+func (c *crawl) visit(ctx context.Context, number int, result fetch.Result) (bool, *goquery.Document, error) {
+	var status *fetch.StatusError
+	if number > 1 && errors.As(result.Err, &status) && status.Code == http.StatusNotFound {
+		return false, nil, nil
+	}
+	p := Page{Number: number, URL: result.URL, FetchedAt: time.Now()}
+
+	err := result.Err
+	var document *goquery.Document
+	if err == nil {
+		document, err = Document(result.Page)
+	}
+	if err != nil {
+		if ctx.Err() != nil {
+			return false, nil, ctx.Err()
+		}
+		p.Err = err
+		return true, nil, c.page(p, nil)
+	}
+
+	var fresh []Item
+	for _, item := range c.spec.Extract(document) {
+		p.Items++
+		if key := c.spec.key(item); key != "" {
+			if c.seen[key] {
+				p.Duplicates++
+				continue
+			}
+			c.seen[key] = true
+		}
+		fresh = append(fresh, item)
+	}
+
+	if err := c.page(p, fresh); err != nil {
+		return false, nil, err
+	}
+	return len(fresh) > 0, document, nil
+}
+
+// pageURL returns the URL of a page from the page template. The first page
+// is always the start URL.
+func (s *Spec) pageURL(number int) string {
+	if number == 1 {
+		return s.StartURL
+	}
+	return strings.ReplaceAll(s.Pagination.PageTemplate, "{page}", strconv.Itoa(number))
+}
+
+// key identifies an item for de-duplication.
+func (s *Spec) key(item Item) string {
+	if id := item[s.IDField]; s.IDField != "" && id != "" {
+		return "id:" + id
+	}
+	if u := item[s.URLField]; s.URLField != "" && u != "" {
+		return "url:" + u
+	}
+	return ""
+}
+
+// nextPage returns the URL the next link on document points to, or an
+// empty string if there is none or the page was skipped.
+func (s *Spec) nextPage(document *goquery.Document) string {
+	selector := s.Pagination.NextSelector
+	if selector == "" || document == nil {
+		return ""
+	}
+	href, ok := document.Find(selector).First().Attr("href")
+	if !ok || strings.TrimSpace(href) == "" {
+		return ""
+	}
+	u, err := document.Url.Parse(strings.TrimSpace(href))
+	if err != nil {
+		return ""
+	}
+	return u.String()
+}
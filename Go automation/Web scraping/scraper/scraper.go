@@ -0,0 +1,97 @@
+package scraper
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/url"
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/TheInvincibleRalph/Go-automation.git/fetch"
+)
+
+// Item is one scraped listing, keyed by field name.
+type Item map[string]string
+
+// Values returns the item's values for columns, in order.
+func (it Item) Values(columns []string) []string {
+	values := make([]string, len(columns))
+	for i, c := range columns {
+		values[i] = it[c]
+	}
+	return values
+}
+
+// Document parses a fetched page.
+func Document(page *fetch.Page) (*goquery.Document, error) {
+	return Parse(bytes.NewReader(page.Body), page.URL)
+}
+
+// Parse reads an HTML page that was served from page. The URL is needed to
+// resolve relative links.
+func Parse(r io.Reader, page *url.URL) (*goquery.Document, error) {
+	document, err := goquery.NewDocumentFromReader(r)
+	if err != nil {
+		return nil, fmt.Errorf("parsing %s: %w", page, err)
+	}
+	document.Url = page
+	return document, nil
+}
+
+// Extract pulls every item out of a page.
+func (s *Spec) Extract(document *goquery.Document) []Item {
+	var items []Item
+	document.Find(s.ItemSelector).Each(func(i int, selection *goquery.Selection) {
+		item := Item{}
+		for _, f := range s.Fields {
+			item[f.Name], _ = f.value(selection, document.Url)
+		}
+		items = append(items, item)
+	})
+	return items
+}
+
+// value reads the field from an item. It reports false if the field's
+// selector or attribute matched nothing.
+func (f Field) value(item *goquery.Selection, page *url.URL) (string, bool) {
+	selection := item
+	if f.Selector != "" {
+		selection = item.Find(f.Selector).First()
+		if selection.Length() == 0 {
+			return "", false
+		}
+	}
+
+	var value string
+	if f.Attribute != "" {
+		v, ok := selection.Attr(f.Attribute)
+		if !ok {
+			return "", false
+		}
+		value = v
+	} else {
+		value = selection.Text()
+	}
+
+	if f.Trim {
+		value = strings.TrimSpace(value)
+	}
+	if f.regex != nil {
+		match := f.regex.FindStringSubmatch(value)
+		switch {
+		case match == nil:
+			value = ""
+		case len(match) > 1:
+			value = match[1]
+		default:
+			value = match[0]
+		}
+	}
+	if f.Absolute && value != "" && page != nil {
+		if u, err := page.Parse(value); err == nil {
+			value = u.String()
+		}
+	}
+	return value, true
+}
@@ -0,0 +1,113 @@
+package scraper
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/TheInvincibleRalph/Go-automation.git/fetch"
+	"github.com/TheInvincibleRalph/Go-automation.git/internal/scrapetest"
+	"github.com/TheInvincibleRalph/Go-automation.git/robots"
+)
+
+// pages are the saved listing pages. There is no third page, so a crawl
+// runs into a 404 there.
+var pages = map[string]string{
+	"/cars":        "jiji-cars-1.html",
+	"/cars?page=2": "jiji-cars-2.html",
+	"/robots.txt":  "robots.txt",
+}
+
+// loadSpec reads the jiji-cars spec and points it at server instead of
+// jiji.ng.
+func loadSpec(t *testing.T, server string) *Spec {
+	t.Helper()
+	spec, err := LoadSpec("../specs/jiji-cars.yaml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	spec.StartURL = server + "/cars"
+	spec.Pagination.PageTemplate = server + "/cars?page={page}"
+	return spec
+}
+
+// crawled is what a crawl saw, with the server's address taken out so that
+// it compares equal from one run to the next.
+type crawled struct {
+	Pages []crawledPage `json:"pages"`
+	Items []Item        `json:"items"`
+}
+
+type crawledPage struct {
+	Number     int    `json:"number"`
+	URL        string `json:"url"`
+	Items      int    `json:"items"`
+	Duplicates int    `json:"duplicates"`
+	Err        string `json:"err,omitempty"`
+}
+
+func TestCrawl(t *testing.T) {
+	tests := []struct {
+		name       string
+		pagination func(*Pagination)
+	}{
+		{"page template", func(p *Pagination) {}},
+		{"next link", func(p *Pagination) {
+			p.PageTemplate = ""
+			p.NextSelector = "a.b-pagination__next"
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := scrapetest.NewServer(t, pages)
+			spec := loadSpec(t, server.URL)
+			tt.pagination(&spec.Pagination)
+			client := &fetch.Client{HTTP: server.Client(), Robots: &robots.Checker{HTTP: server.Client()}}
+
+			var got crawled
+			err := spec.Crawl(context.Background(), client, 5, 2, func(page Page, items []Item) error {
+				p := crawledPage{
+					Number:     page.Number,
+					URL:        strings.TrimPrefix(page.URL, server.URL),
+					Items:      page.Items,
+					Duplicates: page.Duplicates,
+				}
+				if page.Err != nil {
+					p.Err = strings.ReplaceAll(page.Err.Error(), server.URL, "")
+				}
+				got.Pages = append(got.Pages, p)
+				for _, item := range items {
+					item[spec.URLField] = strings.TrimPrefix(item[spec.URLField], server.URL)
+					got.Items = append(got.Items, item)
+				}
+				return nil
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			// Both ways through the pages find the same listings.
+			scrapetest.Golden(t, "jiji-cars.crawl", got)
+		})
+	}
+}
+
+func TestValidate(t *testing.T) {
+	server := scrapetest.NewServer(t, pages)
+	spec := loadSpec(t, server.URL)
+	client := &fetch.Client{HTTP: server.Client()}
+
+	page, err := client.Get(context.Background(), spec.StartURL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	document, err := Document(page)
+	if err != nil {
+		t.Fatal(err)
+	}
+	report := spec.Validate(document)
+	if problems := report.Problems(); len(problems) > 0 {
+		t.Errorf("Validate found problems with the spec: %q", problems)
+	}
+	report.URL = strings.TrimPrefix(report.URL, server.URL)
+	scrapetest.Golden(t, "jiji-cars.validate", report)
+}
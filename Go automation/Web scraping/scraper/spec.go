@@ -0,0 +1,199 @@
+// Package scraper extracts listings from web pages as described by a spec
+// file, so that scraping a new site means writing a spec rather than code.
+package scraper
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"regexp"
+	"strings"
+
+	"github.com/TheInvincibleRalph/Go-automation.git/price"
+	"github.com/andybalholm/cascadia"
+	"gopkg.in/yaml.v3"
+)
+
+// Spec describes one site: where to start, what an item looks like and
+// which fields to pull out of each item.
+type Spec struct {
+	Name     string `yaml:"name"`
+	StartURL string `yaml:"start_url"`
+
+	// ItemSelector matches one element per listing on the page.
+	ItemSelector string `yaml:"item_selector"`
+
+	Fields []Field `yaml:"fields"`
+
+	// Columns lists the fields to output, in order. All fields are output
+	// in the order they are defined if it is empty.
+	Columns []string `yaml:"columns"`
+
+	// IDField and URLField name the fields that identify a listing and
+	// link to it. They are output in columns of their own.
+	IDField  string `yaml:"id_field"`
+	URLField string `yaml:"url_field"`
+
+	Pagination Pagination `yaml:"pagination"`
+}
+
+// Field is one value pulled out of an item.
+type Field struct {
+	Name string `yaml:"name"`
+
+	// Selector picks the element inside the item to read. An empty
+	// selector reads the item element itself. Only the first match is used.
+	Selector string `yaml:"selector"`
+
+	// Attribute is the attribute to read, such as href. The element's text
+	// is read if it is empty.
+	Attribute string `yaml:"attribute"`
+
+	// Trim removes surrounding whitespace from the value.
+	Trim bool `yaml:"trim"`
+
+	// Regex keeps only the part of the value it matches, or its first
+	// group if it has one. A value it does not match becomes empty.
+	Regex string `yaml:"regex"`
+
+	// Absolute resolves the value as a URL against the page it came from,
+	// for links that are relative.
+	Absolute bool `yaml:"absolute"`
+
+	// Type is "price" for fields that hold a price, which are output as
+	// numbers as well as text. Other fields are plain text.
+	Type string `yaml:"type"`
+
+	// Currency is the currency code to assume for prices that do not show
+	// one.
+	Currency string `yaml:"currency"`
+
+	regex *regexp.Regexp
+}
+
+// IsPrice reports whether the field holds a price.
+func (f Field) IsPrice() bool {
+	return f.Type == "price"
+}
+
+// Field returns the field with the given name.
+func (s *Spec) Field(name string) (Field, bool) {
+	for _, f := range s.Fields {
+		if f.Name == name {
+			return f, true
+		}
+	}
+	return Field{}, false
+}
+
+// LoadSpec reads a spec from a YAML file and checks it.
+func LoadSpec(path string) (*Spec, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var spec Spec
+	if err := yaml.Unmarshal(data, &spec); err != nil {
+		return nil, fmt.Errorf("reading %s: %w", path, err)
+	}
+	if err := spec.Check(); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return &spec, nil
+}
+
+// Check makes sure the spec is complete and its selectors and regular
+// expressions compile, and fills in the default columns.
+func (s *Spec) Check() error {
+	if s.Name == "" {
+		return errors.New("spec has no name")
+	}
+	if u, err := url.Parse(s.StartURL); err != nil || !u.IsAbs() {
+		return fmt.Errorf("start_url %q is not an absolute URL", s.StartURL)
+	}
+	if s.ItemSelector == "" {
+		return errors.New("spec has no item_selector")
+	}
+	if _, err := cascadia.ParseGroup(s.ItemSelector); err != nil {
+		return fmt.Errorf("item_selector %q: %w", s.ItemSelector, err)
+	}
+	if len(s.Fields) == 0 {
+		return errors.New("spec has no fields")
+	}
+
+	seen := map[string]bool{}
+	for i := range s.Fields {
+		f := &s.Fields[i]
+		if f.Name == "" {
+			return fmt.Errorf("field %d has no name", i+1)
+		}
+		if seen[f.Name] {
+			return fmt.Errorf("field %s is defined more than once", f.Name)
+		}
+		seen[f.Name] = true
+
+		if f.Selector != "" {
+			if _, err := cascadia.ParseGroup(f.Selector); err != nil {
+				return fmt.Errorf("field %s: selector %q: %w", f.Name, f.Selector, err)
+			}
+		}
+		if f.Regex != "" {
+			re, err := regexp.Compile(f.Regex)
+			if err != nil {
+				return fmt.Errorf("field %s: %w", f.Name, err)
+			}
+			f.regex = re
+		}
+
+		switch f.Type {
+		case "", "text", "price":
+		default:
+			return fmt.Errorf("field %s: unknown type %q, expected text or price", f.Name, f.Type)
+		}
+		if f.Currency != "" {
+			if _, ok := price.LookupCurrency(f.Currency); !ok {
+				return fmt.Errorf("field %s: unknown currency %q", f.Name, f.Currency)
+			}
+		}
+	}
+
+	if len(s.Columns) == 0 {
+		for _, f := range s.Fields {
+			s.Columns = append(s.Columns, f.Name)
+		}
+	}
+	for _, c := range s.Columns {
+		if !seen[c] {
+			return fmt.Errorf("column %s is not a field", c)
+		}
+	}
+	if s.IDField != "" && !seen[s.IDField] {
+		return fmt.Errorf("id_field %s is not a field", s.IDField)
+	}
+	if s.URLField != "" && !seen[s.URLField] {
+		return fmt.Errorf("url_field %s is not a field", s.URLField)
+	}
+
+	p := s.Pagination
+	switch {
+	case p.NextSelector != "" && p.PageTemplate != "":
+		return errors.New("pagination: set next_selector or page_template, not both")
+	case p.NextSelector != "":
+		if _, err := cascadia.ParseGroup(p.NextSelector); err != nil {
+			return fmt.Errorf("pagination: next_selector %q: %w", p.NextSelector, err)
+		}
+	case p.PageTemplate != "":
+		if !strings.Contains(p.PageTemplate, "{page}") {
+			return fmt.Errorf("pagination: page_template %q has no {page}", p.PageTemplate)
+		}
+		if u, err := url.Parse(strings.ReplaceAll(p.PageTemplate, "{page}", "1")); err != nil || !u.IsAbs() {
+			return fmt.Errorf("pagination: page_template %q is not an absolute URL", p.PageTemplate)
+		}
+	}
+	if p.MaxPages < 0 {
+		return fmt.Errorf("pagination: max_pages %d is negative", p.MaxPages)
+	}
+	return nil
+}
@@ -0,0 +1,63 @@
+package scraper
+
+import (
+	"fmt"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+// Report says how well a spec's selectors matched a page.
+type Report struct {
+	URL    string
+	Items  int
+	Fields []FieldReport
+}
+
+// FieldReport counts the items a field was found in, and how many of those
+// gave an empty value.
+type FieldReport struct {
+	Name    string
+	Matched int
+	Empty   int
+}
+
+// Validate runs the spec against a page and counts what each selector
+// matched, without keeping the items.
+func (s *Spec) Validate(document *goquery.Document) Report {
+	report := Report{URL: document.Url.String()}
+	for _, f := range s.Fields {
+		report.Fields = append(report.Fields, FieldReport{Name: f.Name})
+	}
+
+	document.Find(s.ItemSelector).Each(func(i int, selection *goquery.Selection) {
+		report.Items++
+		for j, f := range s.Fields {
+			value, ok := f.value(selection, document.Url)
+			if ok {
+				report.Fields[j].Matched++
+			}
+			if ok && value == "" {
+				report.Fields[j].Empty++
+			}
+		}
+	})
+	return report
+}
+
+// Problems lists the selectors that matched nothing on the page.
+func (r Report) Problems() []string {
+	if r.Items == 0 {
+		return []string{"item_selector matched nothing"}
+	}
+
+	var problems []string
+	for _, f := range r.Fields {
+		switch {
+		case f.Matched == 0:
+			problems = append(problems, fmt.Sprintf("field %s matched nothing in %d items", f.Name, r.Items))
+		case f.Empty == f.Matched:
+			problems = append(problems, fmt.Sprintf("field %s matched but was empty in every item", f.Name))
+		}
+	}
+	return problems
+}
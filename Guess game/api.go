@@ -0,0 +1,30 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/TheInvincibleRalph/Guessing-game.git/api"
+)
+
+// Create a function to serve the game as a JSON API over HTTP
+func serveAPI(args []string) {
+	flags := flag.NewFlagSet("api", flag.ExitOnError)
+	addr := flags.String("addr", ":8080", "address to listen on")
+	ttl := flags.Duration("ttl", 30*time.Minute, "forget games that have not been touched for this long")
+	flags.Parse(args)
+
+	store := api.NewStore(*ttl)
+	go store.SweepEvery(context.Background(), time.Minute)
+
+	srv := &http.Server{
+		Addr:              *addr,
+		Handler:           api.NewHandler(store),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+	log.Printf("Guessing game API listening on %s", *addr)
+	log.Fatal(srv.ListenAndServe())
+}
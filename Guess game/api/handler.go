@@ -0,0 +1,249 @@
+// Package api serves the guessing game as a JSON API so that browsers and
+// bots can play it over HTTP.
+//
+//	POST /games                 create a game
+//	POST /games/{id}/guesses    make a guess
+//	GET  /games/{id}            show the state of a game
+//
+// The secret is never part of a response, not even after the game is over.
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/TheInvincibleRalph/Guessing-game.git/game"
+)
+
+// maxBodySize limits request bodies, which are only ever a few fields.
+const maxBodySize = 1 << 16
+
+// MaxRange is the most numbers a client may ask for a secret to be drawn
+// from.
+const MaxRange = 1_000_000
+
+// CreateRequest is the body of POST /games. Every field is optional; the
+// numeric ones override the chosen difficulty preset.
+type CreateRequest struct {
+	Difficulty  game.Difficulty `json:"difficulty"`
+	Min         *int            `json:"min"`
+	Max         *int            `json:"max"`
+	MaxAttempts *int            `json:"max_attempts"`
+
+	// Seed makes the secret reproducible. A random one is used if omitted.
+	Seed *int64 `json:"seed"`
+}
+
+// GuessRequest is the body of POST /games/{id}/guesses.
+type GuessRequest struct {
+	Guess *int `json:"guess"`
+}
+
+// State describes a game without giving away the secret.
+type State struct {
+	ID         string          `json:"id"`
+	Difficulty game.Difficulty `json:"difficulty"`
+	Min        int             `json:"min"`
+	Max        int             `json:"max"`
+	Attempts   int             `json:"attempts"`
+
+	// AttemptsLeft is omitted when attempts are unlimited.
+	AttemptsLeft *int `json:"attempts_left,omitempty"`
+
+	// Status is "playing", "won" or "lost".
+	Status string `json:"status"`
+	Score  int    `json:"score"`
+}
+
+// GuessResponse is returned for every accepted guess.
+type GuessResponse struct {
+	Guess int `json:"guess"`
+
+	// Result is "too-high", "too-low" or "correct".
+	Result string `json:"result"`
+	State
+}
+
+type errorResponse struct {
+	Error string `json:"error"`
+}
+
+// Handler serves the API for the games in a store.
+type Handler struct {
+	store *Store
+	mux   *http.ServeMux
+}
+
+// NewHandler creates the API handler on top of store.
+func NewHandler(store *Store) *Handler {
+	h := &Handler{store: store, mux: http.NewServeMux()}
+	h.mux.HandleFunc("POST /games", h.create)
+	h.mux.HandleFunc("GET /games/{id}", h.get)
+	h.mux.HandleFunc("POST /games/{id}/guesses", h.guess)
+	return h
+}
+
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	h.mux.ServeHTTP(w, r)
+}
+
+func (h *Handler) create(w http.ResponseWriter, r *http.Request) {
+	var req CreateRequest
+	if err := decode(r, &req); err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	if req.Difficulty == "" {
+		req.Difficulty = game.Normal
+	}
+	cfg, err := game.Preset(game.HigherLower, req.Difficulty)
+	if err != nil {
+		writeError(w, http.StatusUnprocessableEntity, err)
+		return
+	}
+	if req.Min != nil {
+		cfg.Min = *req.Min
+	}
+	if req.Max != nil {
+		cfg.Max = *req.Max
+	}
+	if req.MaxAttempts != nil {
+		cfg.MaxAttempts = *req.MaxAttempts
+	}
+	// Counted as unsigned so that no range overflows; ranges with min not
+	// below max are left for game.New to reject.
+	if cfg.Min < cfg.Max && uint64(cfg.Max)-uint64(cfg.Min) >= MaxRange {
+		writeError(w, http.StatusUnprocessableEntity,
+			fmt.Errorf("range %d to %d is too wide, it may hold at most %d numbers", cfg.Min, cfg.Max, MaxRange))
+		return
+	}
+
+	seed := time.Now().UnixNano()
+	if req.Seed != nil {
+		seed = *req.Seed
+	}
+
+	g, err := game.New(cfg, seed)
+	if err != nil {
+		writeError(w, http.StatusUnprocessableEntity, err)
+		return
+	}
+
+	id, err := h.store.Create(g)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	var state State
+	h.store.Update(id, func(g *game.Game) { state = stateOf(id, g) })
+	w.Header().Set("Location", "/games/"+id)
+	writeJSON(w, http.StatusCreated, state)
+}
+
+func (h *Handler) get(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+
+	var state State
+	if !h.store.Update(id, func(g *game.Game) { state = stateOf(id, g) }) {
+		writeError(w, http.StatusNotFound, fmt.Errorf("game %s not found", id))
+		return
+	}
+	writeJSON(w, http.StatusOK, state)
+}
+
+func (h *Handler) guess(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+
+	var req GuessRequest
+	if err := decode(r, &req); err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	if req.Guess == nil {
+		writeError(w, http.StatusBadRequest, errors.New("missing guess"))
+		return
+	}
+
+	var (
+		resp    GuessResponse
+		outcome game.Outcome
+		err     error
+	)
+	found := h.store.Update(id, func(g *game.Game) {
+		outcome, err = g.Guess(*req.Guess)
+		resp = GuessResponse{Guess: *req.Guess, Result: result(outcome), State: stateOf(id, g)}
+	})
+
+	switch {
+	case !found:
+		writeError(w, http.StatusNotFound, fmt.Errorf("game %s not found", id))
+	case errors.Is(err, game.ErrGameOver):
+		writeError(w, http.StatusConflict, err)
+	case errors.Is(err, game.ErrOutOfRange):
+		writeError(w, http.StatusUnprocessableEntity, fmt.Errorf("%w, it must be between %d and %d", err, resp.Min, resp.Max))
+	case err != nil:
+		writeError(w, http.StatusInternalServerError, err)
+	default:
+		writeJSON(w, http.StatusOK, resp)
+	}
+}
+
+// stateOf describes g. It must only be called while the store is locked.
+func stateOf(id string, g *game.Game) State {
+	cfg := g.Config()
+	state := State{
+		ID:         id,
+		Difficulty: cfg.Difficulty,
+		Min:        cfg.Min,
+		Max:        cfg.Max,
+		Attempts:   g.Attempts(),
+		Status:     "playing",
+		Score:      g.Score(),
+	}
+	if left := g.AttemptsLeft(); left >= 0 {
+		state.AttemptsLeft = &left
+	}
+	switch {
+	case g.Won():
+		state.Status = "won"
+	case g.Over():
+		state.Status = "lost"
+	}
+	return state
+}
+
+func result(o game.Outcome) string {
+	switch o {
+	case game.TooBig:
+		return "too-high"
+	case game.TooSmall:
+		return "too-low"
+	}
+	return "correct"
+}
+
+// decode reads a JSON body into v. An empty body leaves v unchanged.
+func decode(r *http.Request, v any) error {
+	dec := json.NewDecoder(io.LimitReader(r.Body, maxBodySize))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(v); err != nil && !errors.Is(err, io.EOF) {
+		return fmt.Errorf("invalid JSON body: %w", err)
+	}
+	return nil
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
+
+func writeError(w http.ResponseWriter, status int, err error) {
+	writeJSON(w, status, errorResponse{Error: err.Error()})
+}
@@ -0,0 +1,299 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/TheInvincibleRalph/Guessing-game.git/game"
+)
+
+// apiClient sends requests to a test server and keeps every response body,
+// so that tests can check what was given away.
+type apiClient struct {
+	t      *testing.T
+	url    string
+	bodies []string
+}
+
+func newAPI(t *testing.T, ttl time.Duration) (*apiClient, *Store) {
+	t.Helper()
+	store := NewStore(ttl)
+	server := httptest.NewServer(NewHandler(store))
+	t.Cleanup(server.Close)
+	return &apiClient{t: t, url: server.URL}, store
+}
+
+// do sends body to path and decodes the response into out, which may be
+// nil. It returns the status and headers.
+func (c *apiClient) do(method, path, body string, out any) (int, http.Header) {
+	c.t.Helper()
+	req, err := http.NewRequest(method, c.url+path, strings.NewReader(body))
+	if err != nil {
+		c.t.Fatal(err)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		c.t.Fatal(err)
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		c.t.Fatal(err)
+	}
+	c.bodies = append(c.bodies, string(data))
+	if ct := resp.Header.Get("Content-Type"); ct != "application/json" {
+		c.t.Errorf("%s %s: Content-Type %q, want application/json", method, path, ct)
+	}
+	if out != nil {
+		if err := json.Unmarshal(data, out); err != nil {
+			c.t.Fatalf("%s %s: decoding %s: %v", method, path, data, err)
+		}
+	}
+	return resp.StatusCode, resp.Header
+}
+
+func (c *apiClient) create(body string) State {
+	c.t.Helper()
+	var state State
+	status, header := c.do(http.MethodPost, "/games", body, &state)
+	if status != http.StatusCreated {
+		c.t.Fatalf("POST /games %s: status %d, want 201", body, status)
+	}
+	if loc := header.Get("Location"); loc != "/games/"+state.ID {
+		c.t.Errorf("Location = %q, want /games/%s", loc, state.ID)
+	}
+	return state
+}
+
+func (c *apiClient) guess(id string, guess int) (int, GuessResponse) {
+	c.t.Helper()
+	var resp GuessResponse
+	status, _ := c.do(http.MethodPost, "/games/"+id+"/guesses", `{"guess":`+strconv.Itoa(guess)+`}`, &resp)
+	return status, resp
+}
+
+// secretFor returns the secret the API draws for a normal game with seed.
+func secretFor(t *testing.T, cfg game.Config, seed int64) int {
+	t.Helper()
+	g, err := game.New(cfg, seed)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return g.Secret()
+}
+
+func TestCreate(t *testing.T) {
+	c, _ := newAPI(t, time.Hour)
+
+	state := c.create("")
+	if state.Difficulty != game.Normal || state.Min != 1 || state.Max != 100 || state.Status != "playing" {
+		t.Errorf("default game = %+v, want a normal 1-100 game being played", state)
+	}
+	if state.AttemptsLeft == nil || *state.AttemptsLeft != 10 {
+		t.Errorf("default game has attempts_left %v, want 10", state.AttemptsLeft)
+	}
+
+	state = c.create(`{"difficulty":"easy","min":5,"max":50,"seed":3}`)
+	if state.Difficulty != game.Easy || state.Min != 5 || state.Max != 50 {
+		t.Errorf("custom game = %+v, want an easy 5-50 game", state)
+	}
+	if state.AttemptsLeft != nil {
+		t.Errorf("easy game has attempts_left %d, want it omitted", *state.AttemptsLeft)
+	}
+
+	// The widest range allowed holds exactly MaxRange numbers.
+	state = c.create(`{"min":1,"max":1000000}`)
+	if state.Min != 1 || state.Max != MaxRange {
+		t.Errorf("widest game = %+v, want a 1-%d game", state, MaxRange)
+	}
+
+	bad := []struct {
+		body   string
+		status int
+	}{
+		{`{"difficulty":`, http.StatusBadRequest},
+		{`{"difficulty":"normal","colour":"red"}`, http.StatusBadRequest},
+		{`{"difficulty":"impossible"}`, http.StatusUnprocessableEntity},
+		{`{"min":10,"max":10}`, http.StatusUnprocessableEntity},
+		{`{"max_attempts":-1}`, http.StatusUnprocessableEntity},
+		{`{"min":0,"max":9223372036854775807}`, http.StatusUnprocessableEntity},
+		{`{"min":-9223372036854775808,"max":9223372036854775807}`, http.StatusUnprocessableEntity},
+		{`{"min":1,"max":1000001}`, http.StatusUnprocessableEntity},
+	}
+	for _, tt := range bad {
+		var e errorResponse
+		if status, _ := c.do(http.MethodPost, "/games", tt.body, &e); status != tt.status || e.Error == "" {
+			t.Errorf("POST /games %s: status %d error %q, want %d with an error", tt.body, status, e.Error, tt.status)
+		}
+	}
+}
+
+func TestGuessUntilWon(t *testing.T) {
+	c, _ := newAPI(t, time.Hour)
+	cfg, _ := game.Preset(game.HigherLower, game.Normal)
+	secret := secretFor(t, cfg, 99)
+	state := c.create(`{"seed":99}`)
+
+	low, high := 1, 100
+	for attempt := 1; ; attempt++ {
+		guess := low + (high-low)/2
+		status, resp := c.guess(state.ID, guess)
+		if status != http.StatusOK {
+			t.Fatalf("guess %d: status %d", guess, status)
+		}
+		if resp.Guess != guess || resp.Attempts != attempt || *resp.AttemptsLeft != 10-attempt {
+			t.Errorf("guess %d: response %+v, want attempt %d with %d left", guess, resp, attempt, 10-attempt)
+		}
+
+		want := "correct"
+		switch {
+		case guess > secret:
+			want, high = "too-high", guess-1
+		case guess < secret:
+			want, low = "too-low", guess+1
+		}
+		if resp.Result != want {
+			t.Fatalf("guess %d of %d: result %q, want %q", guess, secret, resp.Result, want)
+		}
+		if want == "correct" {
+			if resp.Status != "won" || resp.Score == 0 {
+				t.Errorf("winning response %+v, want status won with a score", resp)
+			}
+			break
+		}
+	}
+
+	var got State
+	if status, _ := c.do(http.MethodGet, "/games/"+state.ID, "", &got); status != http.StatusOK || got.Status != "won" {
+		t.Errorf("GET after winning: status %d state %+v, want 200 and won", status, got)
+	}
+	if status, _ := c.guess(state.ID, secret); status != http.StatusConflict {
+		t.Errorf("guess after winning: status %d, want 409", status)
+	}
+}
+
+func TestGuessErrors(t *testing.T) {
+	c, _ := newAPI(t, time.Hour)
+	state := c.create(`{"max_attempts":1}`)
+
+	var e errorResponse
+	if status, _ := c.do(http.MethodPost, "/games/"+state.ID+"/guesses", `{}`, &e); status != http.StatusBadRequest {
+		t.Errorf("guess without a number: status %d, want 400", status)
+	}
+	if status, _ := c.do(http.MethodPost, "/games/"+state.ID+"/guesses", `{"guess":"ten"}`, &e); status != http.StatusBadRequest {
+		t.Errorf("guess that is not a number: status %d, want 400", status)
+	}
+	if status, _ := c.guess(state.ID, 101); status != http.StatusUnprocessableEntity {
+		t.Errorf("guess out of range: status %d, want 422", status)
+	}
+
+	// None of those used up the only attempt.
+	var got State
+	c.do(http.MethodGet, "/games/"+state.ID, "", &got)
+	if got.Attempts != 0 || got.Status != "playing" {
+		t.Errorf("after rejected guesses: %+v, want no attempts and still playing", got)
+	}
+
+	if status, _ := c.guess("nosuchgame", 5); status != http.StatusNotFound {
+		t.Errorf("guess in unknown game: status %d, want 404", status)
+	}
+	if status, _ := c.do(http.MethodGet, "/games/nosuchgame", "", &e); status != http.StatusNotFound {
+		t.Errorf("GET unknown game: status %d, want 404", status)
+	}
+}
+
+func TestGamesExpire(t *testing.T) {
+	c, store := newAPI(t, 20*time.Millisecond)
+	expired := c.create("")
+	time.Sleep(40 * time.Millisecond)
+
+	fresh := c.create("")
+	if store.Len() != 2 {
+		t.Fatalf("store holds %d games, want 2", store.Len())
+	}
+	store.Sweep()
+	if store.Len() != 1 {
+		t.Errorf("after Sweep the store holds %d games, want 1", store.Len())
+	}
+
+	if status, _ := c.do(http.MethodGet, "/games/"+expired.ID, "", nil); status != http.StatusNotFound {
+		t.Errorf("GET expired game: status %d, want 404", status)
+	}
+	if status, _ := c.do(http.MethodGet, "/games/"+fresh.ID, "", nil); status != http.StatusOK {
+		t.Errorf("GET live game: status %d, want 200", status)
+	}
+}
+
+// TestSecretNeverLeaks plays a game to the end without finding the secret
+// and checks that no response, including errors, ever mentions it.
+func TestSecretNeverLeaks(t *testing.T) {
+	c, _ := newAPI(t, time.Hour)
+
+	// A range of four digit numbers keeps the secret from being mistaken
+	// for a count of attempts or a score.
+	cfg, _ := game.Preset(game.HigherLower, game.Normal)
+	cfg.Min, cfg.Max, cfg.MaxAttempts = 1000, 9999, 3
+	secret := secretFor(t, cfg, 7)
+	state := c.create(`{"min":1000,"max":9999,"max_attempts":3,"seed":7}`)
+
+	wrong := 1000
+	if secret == wrong {
+		wrong++
+	}
+	c.do(http.MethodGet, "/games/"+state.ID, "", nil)
+	c.guess(state.ID, 5)
+	c.do(http.MethodPost, "/games/"+state.ID+"/guesses", `{"guess":`, nil)
+	for i := 0; i < 3; i++ {
+		c.guess(state.ID, wrong)
+	}
+	c.guess(state.ID, secret)
+	c.do(http.MethodGet, "/games/"+state.ID, "", nil)
+
+	var final State
+	c.do(http.MethodGet, "/games/"+state.ID, "", &final)
+	if final.Status != "lost" {
+		t.Fatalf("game ended %q, want lost", final.Status)
+	}
+
+	for _, body := range c.bodies {
+		var v any
+		if err := json.NewDecoder(bytes.NewReader([]byte(body))).Decode(&v); err != nil {
+			t.Fatalf("response %s is not JSON: %v", body, err)
+		}
+		if leaks(v, secret) {
+			t.Errorf("response gives away the secret %d: %s", secret, body)
+		}
+	}
+}
+
+// leaks reports whether v, a decoded JSON value, has a secret field or holds
+// secret anywhere as a number or within a message.
+func leaks(v any, secret int) bool {
+	switch v := v.(type) {
+	case map[string]any:
+		for key, value := range v {
+			if strings.Contains(strings.ToLower(key), "secret") || leaks(value, secret) {
+				return true
+			}
+		}
+	case []any:
+		for _, value := range v {
+			if leaks(value, secret) {
+				return true
+			}
+		}
+	case float64:
+		return v == float64(secret)
+	case string:
+		return strings.Contains(strings.ToLower(v), "secret") || strings.Contains(v, " "+strconv.Itoa(secret))
+	}
+	return false
+}
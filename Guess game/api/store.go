@@ -0,0 +1,98 @@
+package api
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"sync"
+	"time"
+
+	"github.com/TheInvincibleRalph/Guessing-game.git/game"
+)
+
+// Store keeps games in memory between requests. A game expires once it has
+// not been touched for the store's TTL.
+type Store struct {
+	ttl time.Duration
+
+	mu       sync.Mutex
+	sessions map[string]*session
+}
+
+type session struct {
+	game    *game.Game
+	expires time.Time
+}
+
+// NewStore creates a store whose games expire after ttl of inactivity.
+func NewStore(ttl time.Duration) *Store {
+	return &Store{ttl: ttl, sessions: map[string]*session{}}
+}
+
+// Create adds g to the store and returns its new ID.
+func (s *Store) Create(g *game.Game) (string, error) {
+	buf := make([]byte, 16)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	id := hex.EncodeToString(buf)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.sessions[id] = &session{game: g, expires: time.Now().Add(s.ttl)}
+	return id, nil
+}
+
+// Update calls fn with the game stored under id and extends its expiry. The
+// store stays locked while fn runs, so fn may change the game safely but must
+// not call back into the store. It reports false if there is no such game.
+func (s *Store) Update(id string, fn func(g *game.Game)) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	sess, ok := s.sessions[id]
+	if !ok || time.Now().After(sess.expires) {
+		delete(s.sessions, id)
+		return false
+	}
+
+	sess.expires = time.Now().Add(s.ttl)
+	fn(sess.game)
+	return true
+}
+
+// Len returns the number of games in the store, including expired games
+// that have not been swept yet.
+func (s *Store) Len() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.sessions)
+}
+
+// Sweep removes every expired game.
+func (s *Store) Sweep() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	for id, sess := range s.sessions {
+		if now.After(sess.expires) {
+			delete(s.sessions, id)
+		}
+	}
+}
+
+// SweepEvery calls Sweep at the given interval until ctx is cancelled.
+func (s *Store) SweepEvery(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			s.Sweep()
+		}
+	}
+}
@@ -0,0 +1,176 @@
+// Package bot provides computer players for the guessing game. Each bot is a
+// different search strategy, which makes them useful for comparing how many
+// attempts each one needs.
+package bot
+
+import (
+	"fmt"
+	"math/rand"
+
+	"github.com/TheInvincibleRalph/Guessing-game.git/game"
+)
+
+// maxCodeSpace is the largest number of possible codes Knuth is allowed to
+// search, since it keeps every one of them in memory.
+const maxCodeSpace = 1_000_000
+
+// Names lists the built-in bots for each mode.
+var Names = map[game.Mode][]string{
+	game.HigherLower:  {"binary", "random", "linear", "human"},
+	game.BullsAndCows: {"knuth"},
+}
+
+// Bot is a computer player that can play whole games on its own.
+type Bot interface {
+	Play(g *game.Game) error
+}
+
+// New returns the bot with the given name for games played with cfg. Bots
+// that make random choices draw them from rng.
+func New(name string, cfg game.Config, rng *rand.Rand) (Bot, error) {
+	if cfg.IsCode() {
+		if name != "knuth" {
+			return nil, fmt.Errorf("unknown bot %q for %s, expected one of %v", name, cfg.Mode, Names[game.BullsAndCows])
+		}
+		if n := codeSpace(cfg); n > maxCodeSpace {
+			return nil, fmt.Errorf("%d possible codes are too many for the knuth bot", n)
+		}
+		return codeBot{&Knuth{}}, nil
+	}
+
+	switch name {
+	case "binary":
+		return numberBot{&Binary{}}, nil
+	case "random":
+		return numberBot{&Random{rng: rng}}, nil
+	case "linear":
+		return numberBot{&Linear{}}, nil
+	case "human":
+		return numberBot{&HumanLike{rng: rng}}, nil
+	}
+	return nil, fmt.Errorf("unknown bot %q, expected one of %v", name, Names[game.HigherLower])
+}
+
+// numberBot and codeBot plug a player into the engine for its mode.
+type numberBot struct{ game.Player }
+
+func (b numberBot) Play(g *game.Game) error { return game.Play(g, b.Player) }
+
+type codeBot struct{ game.CodePlayer }
+
+func (b codeBot) Play(g *game.Game) error { return game.PlayCode(g, b.CodePlayer) }
+
+// bounds tracks the range the secret can still be in. Every bot embeds it.
+type bounds struct {
+	low, high int
+}
+
+func (b *bounds) Start(cfg game.Config) {
+	b.low, b.high = cfg.Min, cfg.Max
+}
+
+func (b *bounds) Result(guess int, outcome game.Outcome, err error) {
+	if err != nil {
+		return
+	}
+	switch outcome {
+	case game.TooBig:
+		b.high = guess - 1
+	case game.TooSmall:
+		b.low = guess + 1
+	}
+}
+
+// Binary always guesses the middle of the remaining range, so it never needs
+// more than log2(range) attempts.
+type Binary struct {
+	bounds
+}
+
+func (b *Binary) Guess() (int, error) {
+	return b.low + (b.high-b.low)/2, nil
+}
+
+// Random guesses anywhere in the remaining range.
+type Random struct {
+	bounds
+	rng *rand.Rand
+}
+
+func (r *Random) Guess() (int, error) {
+	return r.low + r.rng.Intn(r.high-r.low+1), nil
+}
+
+// Linear counts up from the bottom of the range, ignoring the feedback.
+type Linear struct {
+	next int
+}
+
+func (l *Linear) Start(cfg game.Config) {
+	l.next = cfg.Min
+}
+
+func (l *Linear) Guess() (int, error) {
+	guess := l.next
+	l.next++
+	return guess, nil
+}
+
+func (l *Linear) Result(int, game.Outcome, error) {}
+
+// HumanLike plays the way people tend to: it aims roughly for the middle but
+// prefers round numbers, and now and then forgets what it was told.
+type HumanLike struct {
+	bounds
+	rng *rand.Rand
+
+	// tried remembers earlier guesses so the bot does not repeat itself.
+	tried map[int]bool
+}
+
+func (h *HumanLike) Start(cfg game.Config) {
+	h.bounds.Start(cfg)
+	h.tried = map[int]bool{}
+}
+
+func (h *HumanLike) Guess() (int, error) {
+	width := h.high - h.low + 1
+
+	// Aim somewhere in the middle half of the range
+	guess := h.low + width/4 + h.rng.Intn(width/2+1)
+
+	// Round to a multiple of 5 or 10 when the range is wide enough
+	switch {
+	case width > 50:
+		guess = guess / 10 * 10
+	case width > 20:
+		guess = guess / 5 * 5
+	}
+	guess = min(max(guess, h.low), h.high)
+
+	// Pick something else if that number was already tried
+	if h.tried[guess] {
+		candidates := untried(h.low, h.high, h.tried)
+		guess = candidates[h.rng.Intn(len(candidates))]
+	}
+	h.tried[guess] = true
+	return guess, nil
+}
+
+func (h *HumanLike) Result(guess int, outcome game.Outcome, err error) {
+	// One time in five the feedback is forgotten
+	if h.rng.Intn(5) == 0 {
+		return
+	}
+	h.bounds.Result(guess, outcome, err)
+}
+
+func untried(low, high int, tried map[int]bool) []int {
+	var candidates []int
+	for n := low; n <= high; n++ {
+		if !tried[n] {
+			candidates = append(candidates, n)
+		}
+	}
+	return candidates
+}
@@ -0,0 +1,140 @@
+package bot
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/TheInvincibleRalph/Guessing-game.git/game"
+)
+
+func TestBinaryBound(t *testing.T) {
+	for _, cfg := range []game.Config{
+		{Min: 0, Max: 10},
+		{Min: 1, Max: 100},
+		{Min: 1, Max: 1000},
+		{Min: -7, Max: 8},
+		{Min: 1, Max: 2},
+	} {
+		n := cfg.Max - cfg.Min + 1
+		bound := int(math.Ceil(math.Log2(float64(n)))) + 1
+		for secret := cfg.Min; secret <= cfg.Max; secret++ {
+			g, err := game.NewWithSecret(cfg, secret)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err := game.Play(g, &Binary{}); err != nil {
+				t.Fatal(err)
+			}
+			if !g.Won() || g.Attempts() > bound {
+				t.Errorf("binary in %d-%d, secret %d: won %v in %d attempts, want a win in at most %d",
+					cfg.Min, cfg.Max, secret, g.Won(), g.Attempts(), bound)
+			}
+		}
+	}
+}
+
+func TestEveryBotFinishes(t *testing.T) {
+	for _, cfg := range []game.Config{
+		{Min: 1, Max: 1000},
+		{Min: 1, Max: 100, MaxAttempts: 10},
+	} {
+		for _, name := range Names[game.HigherLower] {
+			rng := rand.New(rand.NewSource(1))
+			b, err := New(name, cfg, rng)
+			if err != nil {
+				t.Fatal(err)
+			}
+			for seed := int64(0); seed < 50; seed++ {
+				g, err := game.New(cfg, seed)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if err := b.Play(g); err != nil {
+					t.Fatalf("%s, seed %d: %v", name, seed, err)
+				}
+				if !g.Over() {
+					t.Fatalf("%s, seed %d: Play returned before the game was over", name, seed)
+				}
+				// With unlimited attempts a game only ends in a win.
+				if cfg.MaxAttempts == 0 && !g.Won() {
+					t.Errorf("%s, seed %d: lost a game with unlimited attempts", name, seed)
+				}
+			}
+		}
+	}
+}
+
+func TestNewUnknownBot(t *testing.T) {
+	if _, err := New("psychic", game.Config{Min: 1, Max: 10}, nil); err == nil {
+		t.Error("New accepted an unknown bot")
+	}
+	if _, err := New("binary", game.Config{Mode: game.BullsAndCows, Digits: 4}, nil); err == nil {
+		t.Error("New gave a number bot for a code game")
+	}
+	if _, err := New("knuth", game.Config{Mode: game.BullsAndCows, Digits: 7}, nil); err == nil {
+		t.Error("New gave the knuth bot 10 million codes to search")
+	}
+}
+
+func TestSimulate(t *testing.T) {
+	tests := []struct {
+		name string
+		cfg  game.Config
+	}{
+		{"binary", game.Config{Min: 1, Max: 100}},
+		{"human", game.Config{Min: 1, Max: 100}},
+		// Linear rarely finds the secret within ten attempts, so most of
+		// these games are lost.
+		{"linear", game.Config{Min: 1, Max: 100, MaxAttempts: 10}},
+		{"random", game.Config{Min: 1, Max: 100, MaxAttempts: 3}},
+	}
+	const games = 500
+	for _, tt := range tests {
+		rng := rand.New(rand.NewSource(7))
+		b, err := New(tt.name, tt.cfg, rng)
+		if err != nil {
+			t.Fatal(err)
+		}
+		r, err := Simulate(b, tt.cfg, games, rng)
+		if err != nil {
+			t.Fatalf("Simulate(%s): %v", tt.name, err)
+		}
+
+		if r.Games != games || r.Wins > games {
+			t.Errorf("Simulate(%s) reports %d wins of %d games, want %d games", tt.name, r.Wins, r.Games, games)
+		}
+		if tt.cfg.MaxAttempts == 0 && r.Wins != games {
+			t.Errorf("Simulate(%s) won %d of %d games with unlimited attempts", tt.name, r.Wins, games)
+		}
+
+		sum, total := 0, 0
+		for attempts, n := range r.Histogram {
+			if attempts < r.MinAttempts || attempts > r.MaxAttempts {
+				t.Errorf("Simulate(%s) has %d games won in %d attempts, outside %d-%d",
+					tt.name, n, attempts, r.MinAttempts, r.MaxAttempts)
+			}
+			sum += n
+			total += attempts * n
+		}
+		if sum != r.Wins {
+			t.Errorf("Simulate(%s) histogram holds %d games, want the %d won", tt.name, sum, r.Wins)
+		}
+		if r.Wins > 0 && math.Abs(r.MeanAttempts-float64(total)/float64(r.Wins)) > 1e-9 {
+			t.Errorf("Simulate(%s) mean is %v, want %v", tt.name, r.MeanAttempts, float64(total)/float64(r.Wins))
+		}
+	}
+}
+
+func TestSimulateIsReproducible(t *testing.T) {
+	cfg := game.Config{Min: 1, Max: 1000}
+	var reports [2]Report
+	for i := range reports {
+		rng := rand.New(rand.NewSource(3))
+		b, _ := New("human", cfg, rng)
+		reports[i], _ = Simulate(b, cfg, 100, rng)
+	}
+	if reports[0].MeanAttempts != reports[1].MeanAttempts || reports[0].MaxAttempts != reports[1].MaxAttempts {
+		t.Errorf("the same seed simulated %+v and then %+v", reports[0], reports[1])
+	}
+}
@@ -0,0 +1,163 @@
+package bot
+
+import (
+	"strings"
+
+	"github.com/TheInvincibleRalph/Guessing-game.git/game"
+)
+
+// maxWork caps the number of comparisons Knuth makes to choose one guess.
+// Beyond it only codes that could still be the secret are considered as
+// guesses, which keeps wide games fast at the cost of a few extra attempts.
+const maxWork = 10_000_000
+
+// Knuth solves BullsAndCows games with Knuth's minimax strategy: every guess
+// is the one whose worst-case feedback leaves the fewest possible secrets.
+type Knuth struct {
+	cfg        game.Config
+	all        []string
+	candidates []string
+
+	// history describes the guesses and feedback so far, and tree remembers
+	// the guess chosen after every history seen before. The strategy is
+	// deterministic, so later games can reuse earlier work.
+	history strings.Builder
+	tree    map[string]string
+}
+
+func (k *Knuth) Start(cfg game.Config) {
+	if k.all == nil || k.cfg != cfg {
+		k.cfg = cfg
+		k.all = allCodes(cfg)
+		k.tree = map[string]string{}
+	}
+	k.candidates = k.all
+	k.history.Reset()
+}
+
+func (k *Knuth) GuessCode() (string, error) {
+	key := k.history.String()
+	if guess, ok := k.tree[key]; ok {
+		return guess, nil
+	}
+
+	var guess string
+	switch {
+	case key == "":
+		guess = opening(k.cfg)
+	case len(k.candidates) <= 2:
+		guess = k.candidates[0]
+	default:
+		guess = k.minimax()
+	}
+	k.tree[key] = guess
+	return guess, nil
+}
+
+func (k *Knuth) CodeResult(guess string, f game.Feedback, err error) {
+	if err != nil {
+		return
+	}
+
+	var remaining []string
+	for _, c := range k.candidates {
+		if game.Compare(c, guess) == f {
+			remaining = append(remaining, c)
+		}
+	}
+	k.candidates = remaining
+	k.history.WriteString(guess + ":" + f.String() + ";")
+}
+
+// minimax picks the guess whose largest group of secrets sharing the same
+// feedback is smallest. Ties go to guesses that could themselves be the
+// secret, then to the lowest code.
+func (k *Knuth) minimax() string {
+	pool := k.all
+	if len(pool)*len(k.candidates) > maxWork {
+		pool = k.candidates
+		if n := maxWork / len(k.candidates); len(pool) > n {
+			pool = pool[:max(n, 1)]
+		}
+	}
+
+	possible := make(map[string]bool, len(k.candidates))
+	for _, c := range k.candidates {
+		possible[c] = true
+	}
+
+	// Feedback is indexed as bulls*11+cows, which fits codes of up to 10
+	// digits.
+	var groups [121]int
+	best, bestWorst, bestPossible := "", len(k.candidates)+1, false
+	for _, guess := range pool {
+		groups = [121]int{}
+		worst := 0
+		for _, c := range k.candidates {
+			f := game.Compare(c, guess)
+			i := f.Bulls*11 + f.Cows
+			groups[i]++
+			worst = max(worst, groups[i])
+		}
+
+		if worst < bestWorst || (worst == bestWorst && possible[guess] && !bestPossible) {
+			best, bestWorst, bestPossible = guess, worst, possible[guess]
+		}
+	}
+	return best
+}
+
+// opening is the fixed first guess. With unique digits it is the first few
+// digits in order; otherwise it follows Knuth's 1122 and uses two digits
+// twice over.
+func opening(cfg game.Config) string {
+	code := make([]byte, cfg.Digits)
+	for i := range code {
+		if cfg.Unique {
+			code[i] = byte('0' + i)
+		} else {
+			code[i] = byte('1' + i*2/max(cfg.Digits, 2))
+		}
+	}
+	return string(code)
+}
+
+// codeSpace returns how many valid codes there are for cfg.
+func codeSpace(cfg game.Config) int {
+	n := 1
+	for i := 0; i < cfg.Digits; i++ {
+		if cfg.Unique {
+			n *= 10 - i
+		} else {
+			n *= 10
+		}
+	}
+	return n
+}
+
+// allCodes lists every valid code for cfg in ascending order.
+func allCodes(cfg game.Config) []string {
+	var codes []string
+	code := make([]byte, cfg.Digits)
+
+	var used [10]bool
+
+	var fill func(pos int)
+	fill = func(pos int) {
+		if pos == len(code) {
+			codes = append(codes, string(code))
+			return
+		}
+		for d := 0; d < 10; d++ {
+			if cfg.Unique && used[d] {
+				continue
+			}
+			used[d] = true
+			code[pos] = byte('0' + d)
+			fill(pos + 1)
+			used[d] = false
+		}
+	}
+	fill(0)
+	return codes
+}
@@ -0,0 +1,123 @@
+package bot
+
+import (
+	"testing"
+
+	"github.com/TheInvincibleRalph/Guessing-game.git/game"
+)
+
+// checked wraps Knuth and checks after every piece of feedback that its
+// candidates are exactly the codes consistent with everything it was told.
+type checked struct {
+	*Knuth
+	t        *testing.T
+	secret   string
+	guesses  []string
+	feedback []game.Feedback
+}
+
+func (c *checked) Start(cfg game.Config) {
+	c.Knuth.Start(cfg)
+	c.guesses, c.feedback = nil, nil
+}
+
+func (c *checked) CodeResult(guess string, f game.Feedback, err error) {
+	c.t.Helper()
+	c.Knuth.CodeResult(guess, f, err)
+	if err != nil {
+		return
+	}
+	c.guesses = append(c.guesses, guess)
+	c.feedback = append(c.feedback, f)
+
+	candidates := map[string]bool{}
+	for _, code := range c.candidates {
+		candidates[code] = true
+	}
+	if !candidates[c.secret] {
+		c.t.Fatalf("secret %s: after %v the candidates no longer include the secret", c.secret, c.guesses)
+	}
+	for _, code := range c.all {
+		consistent := true
+		for i, g := range c.guesses {
+			if game.Compare(code, g) != c.feedback[i] {
+				consistent = false
+				break
+			}
+		}
+		if consistent != candidates[code] {
+			c.t.Fatalf("secret %s: after %v, code %s is consistent %v but a candidate %v",
+				c.secret, c.guesses, code, consistent, candidates[code])
+		}
+	}
+}
+
+func TestKnuthSolvesEveryCode(t *testing.T) {
+	cfg := game.Config{Mode: game.BullsAndCows, Digits: 4, Unique: true}
+	codes := allCodes(cfg)
+	if len(codes) != 5040 {
+		t.Fatalf("%d codes of 4 unique digits, want 5040", len(codes))
+	}
+
+	k := &Knuth{}
+	worst := 0
+	for i, secret := range codes {
+		if testing.Short() && i%10 != 0 {
+			continue
+		}
+		g, err := game.NewWithCode(cfg, secret)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		// Checking the candidates costs a pass over every code per guess,
+		// so only some of the games do it.
+		var p game.CodePlayer = k
+		if i%50 == 0 {
+			p = &checked{Knuth: k, t: t, secret: secret}
+		}
+		if err := game.PlayCode(g, p); err != nil {
+			t.Fatal(err)
+		}
+		if !g.Won() {
+			t.Fatalf("secret %s: not solved", secret)
+		}
+		worst = max(worst, g.Attempts())
+	}
+	if worst > 7 {
+		t.Errorf("knuth needed %d guesses for some code, want at most 7", worst)
+	}
+}
+
+func TestKnuthRepeatedDigits(t *testing.T) {
+	cfg := game.Config{Mode: game.BullsAndCows, Digits: 3}
+	k := &Knuth{}
+	for _, secret := range []string{"000", "112", "999", "390"} {
+		g, err := game.NewWithCode(cfg, secret)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := game.PlayCode(g, &checked{Knuth: k, t: t, secret: secret}); err != nil {
+			t.Fatal(err)
+		}
+		if !g.Won() {
+			t.Errorf("secret %s: not solved", secret)
+		}
+	}
+}
+
+func TestOpening(t *testing.T) {
+	tests := []struct {
+		cfg  game.Config
+		want string
+	}{
+		{game.Config{Digits: 4, Unique: true}, "0123"},
+		{game.Config{Digits: 4}, "1122"},
+		{game.Config{Digits: 1}, "1"},
+	}
+	for _, tt := range tests {
+		if got := opening(tt.cfg); got != tt.want {
+			t.Errorf("opening(%+v) = %q, want %q", tt.cfg, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,58 @@
+package bot
+
+import (
+	"math/rand"
+
+	"github.com/TheInvincibleRalph/Guessing-game.git/game"
+)
+
+// Report summarises many games played by one bot.
+type Report struct {
+	Games int
+	Wins  int
+
+	// MinAttempts, MaxAttempts and MeanAttempts describe the games that
+	// were won.
+	MinAttempts  int
+	MaxAttempts  int
+	MeanAttempts float64
+
+	// Histogram maps a number of attempts to how many won games took that
+	// many.
+	Histogram map[int]int
+}
+
+// Simulate plays the given number of games with a fresh secret each time and
+// reports how many attempts the bot needed. Each game is seeded from rng, so
+// the same rng seed plays out the same way every time.
+func Simulate(b Bot, cfg game.Config, games int, rng *rand.Rand) (Report, error) {
+	report := Report{Games: games, Histogram: map[int]int{}}
+
+	total := 0
+	for i := 0; i < games; i++ {
+		g, err := game.New(cfg, rng.Int63())
+		if err != nil {
+			return report, err
+		}
+		if err := b.Play(g); err != nil {
+			return report, err
+		}
+		if !g.Won() {
+			continue
+		}
+
+		attempts := g.Attempts()
+		if report.Wins == 0 || attempts < report.MinAttempts {
+			report.MinAttempts = attempts
+		}
+		report.MaxAttempts = max(report.MaxAttempts, attempts)
+		report.Histogram[attempts]++
+		report.Wins++
+		total += attempts
+	}
+
+	if report.Wins > 0 {
+		report.MeanAttempts = float64(total) / float64(report.Wins)
+	}
+	return report, nil
+}
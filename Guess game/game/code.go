@@ -0,0 +1,138 @@
+package game
+
+import (
+	"errors"
+	"fmt"
+	"math/rand"
+	"time"
+)
+
+var (
+	// ErrWrongMode is returned when a guess is made for the other mode, for
+	// example a number in a BullsAndCows game.
+	ErrWrongMode = errors.New("guess does not fit the game mode")
+
+	// ErrInvalidCode is returned for codes of the wrong length, with
+	// characters other than digits, or with repeated digits when the game
+	// asks for unique ones. Such guesses do not count as an attempt.
+	ErrInvalidCode = errors.New("guess is not a valid code")
+)
+
+// Feedback scores a code guess. Bulls are digits in the right place, cows
+// are digits in the code but in the wrong place.
+type Feedback struct {
+	Bulls int `json:"bulls"`
+	Cows  int `json:"cows"`
+}
+
+func (f Feedback) String() string {
+	return fmt.Sprintf("%d %s, %d %s", f.Bulls, plural(f.Bulls, "bull", "bulls"), f.Cows, plural(f.Cows, "cow", "cows"))
+}
+
+func plural(n int, one, many string) string {
+	if n == 1 {
+		return one
+	}
+	return many
+}
+
+// Compare scores guess against secret. Both must be strings of digits of the
+// same length.
+func Compare(secret, guess string) Feedback {
+	var f Feedback
+	var inSecret, inGuess [10]int
+	for i := 0; i < len(secret); i++ {
+		if secret[i] == guess[i] {
+			f.Bulls++
+			continue
+		}
+		inSecret[secret[i]-'0']++
+		inGuess[guess[i]-'0']++
+	}
+	for d := range inSecret {
+		f.Cows += min(inSecret[d], inGuess[d])
+	}
+	return f
+}
+
+// ValidCode reports whether code is a possible secret under cfg.
+func (c Config) ValidCode(code string) bool {
+	if len(code) != c.Digits {
+		return false
+	}
+
+	var seen [10]bool
+	for i := 0; i < len(code); i++ {
+		if code[i] < '0' || code[i] > '9' {
+			return false
+		}
+		if c.Unique && seen[code[i]-'0'] {
+			return false
+		}
+		seen[code[i]-'0'] = true
+	}
+	return true
+}
+
+// randomCode draws a code for cfg from rng.
+func randomCode(cfg Config, rng *rand.Rand) string {
+	code := make([]byte, cfg.Digits)
+	if cfg.Unique {
+		for i, d := range rng.Perm(10)[:cfg.Digits] {
+			code[i] = byte('0' + d)
+		}
+		return string(code)
+	}
+
+	for i := range code {
+		code[i] = byte('0' + rng.Intn(10))
+	}
+	return string(code)
+}
+
+// NewWithCode starts a BullsAndCows game with a known secret code.
+func NewWithCode(cfg Config, code string) (*Game, error) {
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+	if !cfg.IsCode() {
+		return nil, ErrWrongMode
+	}
+	if !cfg.ValidCode(code) {
+		return nil, fmt.Errorf("secret %q is not a valid code for %d digits", code, cfg.Digits)
+	}
+
+	g := &Game{cfg: cfg, code: code, now: time.Now}
+	g.started = g.now()
+	return g, nil
+}
+
+// GuessCode scores a guess in a BullsAndCows game and counts it as an
+// attempt. The game ends when every digit is a bull or the attempts run out.
+func (g *Game) GuessCode(guess string) (Feedback, error) {
+	if !g.cfg.IsCode() {
+		return Feedback{}, ErrWrongMode
+	}
+	if g.over {
+		return Feedback{}, ErrGameOver
+	}
+	if !g.cfg.ValidCode(guess) {
+		g.record(Move{Action: "guess", Code: guess, Response: ErrInvalidCode.Error()})
+		return Feedback{}, ErrInvalidCode
+	}
+
+	g.attempts++
+	f := Compare(g.code, guess)
+	g.won = f.Bulls == g.cfg.Digits
+	g.record(Move{Action: "guess", Code: guess, Response: f.String()})
+
+	if g.won || (g.cfg.MaxAttempts > 0 && g.attempts >= g.cfg.MaxAttempts) {
+		g.over = true
+		g.finished = g.now()
+	}
+	return f, nil
+}
+
+// Code returns the secret code of a BullsAndCows game. It should only be
+// revealed once the game is over.
+func (g *Game) Code() string { return g.code }
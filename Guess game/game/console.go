@@ -0,0 +1,192 @@
+package game
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// Console is the Player for a human at a text interface. Input is read a
+// line at a time, so it works the same on a terminal, a pipe or a script.
+type Console struct {
+	in  *bufio.Scanner
+	out io.Writer
+
+	// game is the game being played, needed to answer hints.
+	game *Game
+}
+
+// NewConsole creates a console that reads guesses from in and writes prompts
+// and results to out.
+func NewConsole(in io.Reader, out io.Writer) *Console {
+	return &Console{in: bufio.NewScanner(in), out: out}
+}
+
+// AskName prompts for the player's name. It returns "anonymous" if nothing
+// is entered.
+func (c *Console) AskName() string {
+	fmt.Fprint(c.out, "What is your name? ")
+	if c.in.Scan() {
+		if name := strings.TrimSpace(c.in.Text()); name != "" {
+			return name
+		}
+	}
+	return "anonymous"
+}
+
+// Play runs g until it is won, lost or abandoned. Besides numbers the player
+// may type "hint" to narrow the range or "quit" to give up. Input that is not
+// a number, or is outside the range, is rejected without using an attempt,
+// and so are invalid codes in a BullsAndCows game. Running out of input
+// abandons the game.
+func (c *Console) Play(g *Game) error {
+	c.game = g
+
+	var err error
+	if g.Config().IsCode() {
+		err = PlayCode(g, c)
+	} else {
+		err = Play(g, c)
+	}
+	if err != nil {
+		return err
+	}
+
+	c.summary(g)
+	return c.in.Err()
+}
+
+// Start introduces the game.
+func (c *Console) Start(cfg Config) {
+	if cfg.IsCode() {
+		fmt.Fprintf(c.out, "Guess the secret code of %s. Type \"quit\" to give up.\n", describeCode(cfg))
+		return
+	}
+	fmt.Fprintf(c.out, "Guess the secret number between %d and %d. Type \"hint\" for a hint or \"quit\" to give up.\n", cfg.Min, cfg.Max)
+}
+
+// Guess prompts until a number or "quit" is entered, answering hints and
+// complaining about anything it cannot parse along the way.
+func (c *Console) Guess() (int, error) {
+	for {
+		fmt.Fprint(c.out, "Please print your guess: ")
+		if !c.in.Scan() {
+			fmt.Fprintln(c.out)
+			return 0, ErrQuit
+		}
+
+		input := strings.TrimSpace(c.in.Text())
+		switch strings.ToLower(input) {
+		case "":
+			continue
+		case "quit":
+			return 0, ErrQuit
+		case "hint":
+			if c.game != nil {
+				low, high := c.game.Hint()
+				fmt.Fprintf(c.out, "Hint: the secret is between %d and %d\n", low, high)
+			}
+			continue
+		}
+
+		guess, err := strconv.Atoi(input)
+		if err != nil {
+			fmt.Fprintf(c.out, "%q is not a number, please try again\n", input)
+			continue
+		}
+		return guess, nil
+	}
+}
+
+// Result tells the player how their guess compares to the secret.
+func (c *Console) Result(guess int, outcome Outcome, err error) {
+	if errors.Is(err, ErrOutOfRange) && c.game != nil {
+		cfg := c.game.Config()
+		fmt.Fprintf(c.out, "Your guess must be between %d and %d\n", cfg.Min, cfg.Max)
+		return
+	}
+
+	switch outcome {
+	case TooBig:
+		fmt.Fprintln(c.out, "Your guess is too big")
+	case TooSmall:
+		fmt.Fprintln(c.out, "Your guess is too small")
+	case Correct:
+		fmt.Fprintln(c.out, "YOU GOT IT!")
+		return
+	}
+	if c.game != nil {
+		if left := c.game.AttemptsLeft(); left > 0 {
+			fmt.Fprintln(c.out, "Attempts left:", left)
+		}
+	}
+}
+
+// GuessCode prompts until a code or "quit" is entered. Whether the code is
+// valid is left to the game.
+func (c *Console) GuessCode() (string, error) {
+	for {
+		fmt.Fprint(c.out, "Please print your code: ")
+		if !c.in.Scan() {
+			fmt.Fprintln(c.out)
+			return "", ErrQuit
+		}
+
+		input := strings.TrimSpace(c.in.Text())
+		switch strings.ToLower(input) {
+		case "":
+			continue
+		case "quit":
+			return "", ErrQuit
+		case "hint":
+			fmt.Fprintln(c.out, "There are no hints in bulls and cows")
+			continue
+		}
+		return input, nil
+	}
+}
+
+// CodeResult tells the player how many bulls and cows their code scored.
+func (c *Console) CodeResult(guess string, f Feedback, err error) {
+	if errors.Is(err, ErrInvalidCode) && c.game != nil {
+		fmt.Fprintf(c.out, "Your code must be %s\n", describeCode(c.game.Config()))
+		return
+	}
+
+	if c.game != nil && c.game.Won() {
+		fmt.Fprintln(c.out, "YOU GOT IT!")
+		return
+	}
+	fmt.Fprintln(c.out, f)
+	if c.game != nil {
+		if left := c.game.AttemptsLeft(); left > 0 {
+			fmt.Fprintln(c.out, "Attempts left:", left)
+		}
+	}
+}
+
+func describeCode(cfg Config) string {
+	if cfg.Unique {
+		return fmt.Sprintf("%d different digits", cfg.Digits)
+	}
+	return fmt.Sprintf("%d digits", cfg.Digits)
+}
+
+func (c *Console) summary(g *Game) {
+	secret := strconv.Itoa(g.Secret())
+	if g.Config().IsCode() {
+		secret = g.Code()
+	}
+
+	switch {
+	case g.Won():
+		fmt.Fprintf(c.out, "You won in %d attempts. Score: %d\n", g.Attempts(), g.Score())
+	case g.AttemptsLeft() == 0:
+		fmt.Fprintln(c.out, "Out of attempts! The secret was", secret)
+	default:
+		fmt.Fprintln(c.out, "You gave up. The secret was", secret)
+	}
+}
@@ -0,0 +1,178 @@
+package game
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+// TestConsoleTranscripts plays whole games through a Console from a script
+// of typed lines and checks everything the player would have seen.
+func TestConsoleTranscripts(t *testing.T) {
+	normal := Config{Mode: HigherLower, Difficulty: Normal, Min: 1, Max: 100, MaxAttempts: 10}
+	short := Config{Mode: HigherLower, Difficulty: Easy, Min: 1, Max: 10, MaxAttempts: 2}
+	code := Config{Mode: BullsAndCows, Difficulty: Normal, Digits: 4, Unique: true, MaxAttempts: 10}
+
+	const (
+		intro     = "Guess the secret number between 1 and 100. Type \"hint\" for a hint or \"quit\" to give up.\n"
+		introTen  = "Guess the secret number between 1 and 10. Type \"hint\" for a hint or \"quit\" to give up.\n"
+		introCode = "Guess the secret code of 4 different digits. Type \"quit\" to give up.\n"
+		prompt    = "Please print your guess: "
+		promptC   = "Please print your code: "
+	)
+
+	tests := []struct {
+		name   string
+		cfg    Config
+		secret int
+		code   string
+		script string
+		want   string
+	}{
+		{
+			name:   "win with bad input and a hint",
+			cfg:    normal,
+			secret: 42,
+			script: "abc\n\n500\n50\nhint\n25\n 42 \n",
+			want: intro +
+				prompt + "\"abc\" is not a number, please try again\n" +
+				prompt + prompt + "Your guess must be between 1 and 100\n" +
+				prompt + "Your guess is too big\nAttempts left: 9\n" +
+				prompt + "Hint: the secret is between 26 and 49\n" +
+				prompt + "Your guess is too small\nAttempts left: 8\n" +
+				prompt + "YOU GOT IT!\n" +
+				"You won in 3 attempts. Score: 1600\n",
+		},
+		{
+			name:   "out of attempts",
+			cfg:    short,
+			secret: 7,
+			script: "1\n2\n3\n",
+			want: introTen +
+				prompt + "Your guess is too small\nAttempts left: 1\n" +
+				prompt + "Your guess is too small\n" +
+				"Out of attempts! The secret was 7\n",
+		},
+		{
+			name:   "quit",
+			cfg:    short,
+			secret: 7,
+			script: "9\nQUIT\n5\n",
+			want: introTen +
+				prompt + "Your guess is too big\nAttempts left: 1\n" +
+				prompt + "You gave up. The secret was 7\n",
+		},
+		{
+			name:   "input runs out",
+			cfg:    short,
+			secret: 7,
+			script: "",
+			want:   introTen + prompt + "\nYou gave up. The secret was 7\n",
+		},
+		{
+			name:   "bulls and cows",
+			cfg:    code,
+			code:   "1234",
+			script: "12\n1123\n1243\nhint\n1234\n",
+			want: introCode +
+				promptC + "Your code must be 4 different digits\n" +
+				promptC + "Your code must be 4 different digits\n" +
+				promptC + "2 bulls, 2 cows\nAttempts left: 9\n" +
+				promptC + "There are no hints in bulls and cows\n" +
+				promptC + "YOU GOT IT!\n" +
+				"You won in 2 attempts. Score: 1900\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var (
+				g   *Game
+				err error
+			)
+			if tt.cfg.IsCode() {
+				g, err = NewWithCode(tt.cfg, tt.code)
+			} else {
+				g, err = NewWithSecret(tt.cfg, tt.secret)
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			fixedClock(g, 0)
+
+			var out strings.Builder
+			if err := NewConsole(strings.NewReader(tt.script), &out).Play(g); err != nil {
+				t.Fatal(err)
+			}
+			if out.String() != tt.want {
+				t.Errorf("transcript differs\ngot:\n%s\nwant:\n%s", out.String(), tt.want)
+			}
+		})
+	}
+}
+
+// TestConsoleReplay checks that a game played at the console leaves a
+// transcript that replays to the same result.
+func TestConsoleReplay(t *testing.T) {
+	cfg, err := Preset(HigherLower, Hard)
+	if err != nil {
+		t.Fatal(err)
+	}
+	g, err := New(cfg, 7)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Script a binary search, which always finds the secret within the 10
+	// guesses allowed, by playing it first on a second game with the same
+	// seed.
+	var script strings.Builder
+	script.WriteString("hint\n")
+	low, high := cfg.Min, cfg.Max
+	probe, _ := New(cfg, 7)
+	for !probe.Over() {
+		guess := low + (high-low)/2
+		outcome, _ := probe.Guess(guess)
+		fmt.Fprintln(&script, guess)
+		switch outcome {
+		case TooBig:
+			high = guess - 1
+		case TooSmall:
+			low = guess + 1
+		}
+	}
+
+	var out strings.Builder
+	if err := NewConsole(strings.NewReader(script.String()), &out).Play(g); err != nil {
+		t.Fatal(err)
+	}
+	if !g.Won() {
+		t.Fatalf("binary search lost the game:\n%s", out.String())
+	}
+
+	replayed, err := Replay(g.Transcript())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if replayed.Attempts() != g.Attempts() || replayed.Hints() != 1 {
+		t.Errorf("replay took %d attempts and %d hints, game took %d and 1", replayed.Attempts(), replayed.Hints(), g.Attempts())
+	}
+}
+
+func TestAskName(t *testing.T) {
+	tests := map[string]string{
+		"Ada\n":     "Ada",
+		"  Bob  \n": "Bob",
+		"\n":        "anonymous",
+		"":          "anonymous",
+	}
+	for input, want := range tests {
+		var out strings.Builder
+		if got := NewConsole(strings.NewReader(input), &out).AskName(); got != want {
+			t.Errorf("AskName(%q) = %q, want %q", input, got, want)
+		}
+		if out.String() != "What is your name? " {
+			t.Errorf("AskName(%q) prompted %q", input, out.String())
+		}
+	}
+}
@@ -0,0 +1,132 @@
+package game
+
+import (
+	"fmt"
+	"math"
+)
+
+// Difficulty names one of the built-in game presets.
+type Difficulty string
+
+const (
+	Easy   Difficulty = "easy"
+	Normal Difficulty = "normal"
+	Hard   Difficulty = "hard"
+)
+
+// Mode names one of the games the engine can play.
+type Mode string
+
+const (
+	// HigherLower is the original game: guess a number and be told whether
+	// it is too big or too small.
+	HigherLower Mode = "higher-lower"
+
+	// BullsAndCows has a secret code of digits. Every guess is scored with
+	// bulls (right digit, right place) and cows (right digit, wrong place).
+	BullsAndCows Mode = "bulls-and-cows"
+)
+
+// Config describes the rules of a single game.
+type Config struct {
+	// Mode is HigherLower when empty.
+	Mode       Mode       `json:"mode,omitempty"`
+	Difficulty Difficulty `json:"difficulty"`
+
+	// Min and Max are the inclusive bounds of the secret number in a
+	// HigherLower game.
+	Min int `json:"min,omitempty"`
+	Max int `json:"max,omitempty"`
+
+	// Digits is the length of the secret code in a BullsAndCows game, and
+	// Unique whether its digits are all different.
+	Digits int  `json:"digits,omitempty"`
+	Unique bool `json:"unique,omitempty"`
+
+	// MaxAttempts is the number of guesses allowed. Zero means unlimited.
+	MaxAttempts int `json:"max_attempts"`
+}
+
+// presets holds the default rules for every difficulty. Easy keeps the
+// original 0–10 range of the game and lets the player guess forever.
+var presets = map[Difficulty]Config{
+	Easy:   {Difficulty: Easy, Min: 0, Max: 10, MaxAttempts: 0},
+	Normal: {Difficulty: Normal, Min: 1, Max: 100, MaxAttempts: 10},
+	Hard:   {Difficulty: Hard, Min: 1, Max: 1000, MaxAttempts: 10},
+}
+
+// codePresets holds the BullsAndCows rules. Normal is the classic game of
+// four different digits.
+var codePresets = map[Difficulty]Config{
+	Easy:   {Mode: BullsAndCows, Difficulty: Easy, Digits: 3, Unique: true, MaxAttempts: 0},
+	Normal: {Mode: BullsAndCows, Difficulty: Normal, Digits: 4, Unique: true, MaxAttempts: 10},
+	Hard:   {Mode: BullsAndCows, Difficulty: Hard, Digits: 4, Unique: false, MaxAttempts: 10},
+}
+
+// Preset returns the rules for the given mode and difficulty.
+func Preset(mode Mode, d Difficulty) (Config, error) {
+	var (
+		cfg Config
+		ok  bool
+	)
+	switch mode {
+	case HigherLower, "":
+		cfg, ok = presets[d]
+	case BullsAndCows:
+		cfg, ok = codePresets[d]
+	default:
+		return Config{}, fmt.Errorf("unknown mode %q, expected %s or %s", mode, HigherLower, BullsAndCows)
+	}
+	if !ok {
+		return Config{}, fmt.Errorf("unknown difficulty %q, expected easy, normal or hard", d)
+	}
+
+	cfg.Mode = mode
+	if cfg.Mode == "" {
+		cfg.Mode = HigherLower
+	}
+	return cfg, nil
+}
+
+// Validate reports whether the config describes a playable game.
+func (c Config) Validate() error {
+	if c.MaxAttempts < 0 {
+		return fmt.Errorf("max attempts (%d) cannot be negative", c.MaxAttempts)
+	}
+
+	switch c.Mode {
+	case HigherLower, "":
+		if c.Min >= c.Max {
+			return fmt.Errorf("min (%d) must be smaller than max (%d)", c.Min, c.Max)
+		}
+		// The secret is drawn from Max-Min+1 numbers, which has to fit in
+		// an int.
+		if span := c.Max - c.Min; span < 0 || span == math.MaxInt {
+			return fmt.Errorf("range %d to %d is too wide", c.Min, c.Max)
+		}
+	case BullsAndCows:
+		if c.Digits < 1 || c.Digits > 10 {
+			return fmt.Errorf("digits (%d) must be between 1 and 10", c.Digits)
+		}
+	default:
+		return fmt.Errorf("unknown mode %q", c.Mode)
+	}
+	return nil
+}
+
+// IsCode reports whether the config is for a BullsAndCows game.
+func (c Config) IsCode() bool {
+	return c.Mode == BullsAndCows
+}
+
+// multiplier scales the score so harder games are worth more.
+func (d Difficulty) multiplier() int {
+	switch d {
+	case Hard:
+		return 3
+	case Normal:
+		return 2
+	default:
+		return 1
+	}
+}
@@ -0,0 +1,229 @@
+package game
+
+import (
+	"errors"
+	"fmt"
+	"math/rand"
+	"time"
+)
+
+var (
+	// ErrGameOver is returned when guessing in a game that has already ended.
+	ErrGameOver = errors.New("game is over")
+
+	// ErrOutOfRange is returned for guesses outside the configured range.
+	// Such guesses do not count as an attempt.
+	ErrOutOfRange = errors.New("guess is out of range")
+)
+
+// Outcome is the result of comparing a guess with the secret.
+type Outcome int
+
+const (
+	TooSmall Outcome = iota
+	TooBig
+	Correct
+)
+
+func (o Outcome) String() string {
+	switch o {
+	case TooSmall:
+		return "too small"
+	case TooBig:
+		return "too big"
+	case Correct:
+		return "correct"
+	}
+	return fmt.Sprintf("Outcome(%d)", int(o))
+}
+
+// Game holds the state of one round of the guessing game. It does no I/O of
+// its own, callers feed it guesses and decide how to show the outcomes.
+type Game struct {
+	cfg    Config
+	secret int
+
+	// code is the secret of a BullsAndCows game.
+	code string
+
+	// seed is the seed the secret was drawn with, nil if the secret was
+	// given directly.
+	seed *int64
+
+	// moves records everything that happened, for the transcript.
+	moves []Move
+
+	attempts int
+	hints    int
+	won      bool
+	over     bool
+
+	// low and high are the bounds the secret is known to lie within, given
+	// the guesses and hints so far.
+	low, high int
+
+	started  time.Time
+	finished time.Time
+
+	// now is the clock used to time the game.
+	now func() time.Time
+}
+
+// New starts a game with a secret drawn from its own random source seeded
+// with seed. The same config and seed always give the same secret, which is
+// what makes games reproducible.
+func New(cfg Config, seed int64) (*Game, error) {
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+
+	var (
+		rng = rand.New(rand.NewSource(seed))
+		g   *Game
+		err error
+	)
+	if cfg.IsCode() {
+		g, err = NewWithCode(cfg, randomCode(cfg, rng))
+	} else {
+		g, err = NewWithSecret(cfg, cfg.RandomSecret(rng))
+	}
+	if err != nil {
+		return nil, err
+	}
+	g.seed = &seed
+	return g, nil
+}
+
+// RandomSecret draws a HigherLower secret from rng, anywhere from Min to
+// Max inclusive. The config must be valid.
+func (c Config) RandomSecret(rng *rand.Rand) int {
+	return c.Min + rng.Intn(c.Max-c.Min+1)
+}
+
+// NewWithSecret starts a HigherLower game with a known secret.
+func NewWithSecret(cfg Config, secret int) (*Game, error) {
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+	if cfg.IsCode() {
+		return nil, ErrWrongMode
+	}
+	if secret < cfg.Min || secret > cfg.Max {
+		return nil, fmt.Errorf("secret %d is outside %d-%d", secret, cfg.Min, cfg.Max)
+	}
+
+	g := &Game{cfg: cfg, secret: secret, low: cfg.Min, high: cfg.Max, now: time.Now}
+	g.started = g.now()
+	return g, nil
+}
+
+// Guess compares guess with the secret and counts it as an attempt. The game
+// ends when the guess is correct or the attempts run out.
+func (g *Game) Guess(guess int) (Outcome, error) {
+	if g.cfg.IsCode() {
+		return 0, ErrWrongMode
+	}
+	if g.over {
+		return 0, ErrGameOver
+	}
+	if guess < g.cfg.Min || guess > g.cfg.Max {
+		g.record(Move{Action: "guess", Guess: guess, Response: ErrOutOfRange.Error()})
+		return 0, ErrOutOfRange
+	}
+
+	g.attempts++
+
+	var outcome Outcome
+	switch {
+	case guess > g.secret:
+		outcome = TooBig
+		g.high = min(g.high, guess-1)
+	case guess < g.secret:
+		outcome = TooSmall
+		g.low = max(g.low, guess+1)
+	default:
+		outcome = Correct
+		g.won = true
+	}
+
+	g.record(Move{Action: "guess", Guess: guess, Response: outcome.String()})
+
+	if g.won || (g.cfg.MaxAttempts > 0 && g.attempts >= g.cfg.MaxAttempts) {
+		g.over = true
+		g.finished = g.now()
+	}
+	return outcome, nil
+}
+
+// Hint halves the range the secret is known to lie within and returns the
+// new bounds. Hints do not use up attempts but reduce the score. There are no
+// hints in a BullsAndCows game.
+func (g *Game) Hint() (low, high int) {
+	if g.over || g.cfg.IsCode() || g.low == g.high {
+		return g.low, g.high
+	}
+
+	g.hints++
+	mid := g.low + (g.high-g.low)/2
+	if g.secret <= mid {
+		g.high = mid
+	} else {
+		g.low = mid + 1
+	}
+	g.record(Move{Action: "hint", Response: fmt.Sprintf("%d-%d", g.low, g.high)})
+	return g.low, g.high
+}
+
+// Quit abandons the game. An abandoned game counts as lost.
+func (g *Game) Quit() {
+	if g.over {
+		return
+	}
+	g.over = true
+	g.finished = g.now()
+	g.record(Move{Action: "quit"})
+}
+
+// Config returns the rules the game is played with.
+func (g *Game) Config() Config { return g.cfg }
+
+// Attempts returns the number of guesses made so far.
+func (g *Game) Attempts() int { return g.attempts }
+
+// AttemptsLeft returns the remaining guesses, or -1 if they are unlimited.
+func (g *Game) AttemptsLeft() int {
+	if g.cfg.MaxAttempts == 0 {
+		return -1
+	}
+	return g.cfg.MaxAttempts - g.attempts
+}
+
+// Hints returns the number of hints taken so far.
+func (g *Game) Hints() int { return g.hints }
+
+// Over reports whether the game has ended.
+func (g *Game) Over() bool { return g.over }
+
+// Won reports whether the secret was guessed.
+func (g *Game) Won() bool { return g.won }
+
+// Secret returns the secret number of a HigherLower game. It should only be
+// revealed once the game is over.
+func (g *Game) Secret() int { return g.secret }
+
+// Elapsed returns how long the game took, or has taken so far.
+func (g *Game) Elapsed() time.Duration {
+	if g.over {
+		return g.finished.Sub(g.started)
+	}
+	return g.now().Sub(g.started)
+}
+
+// Score returns the points earned for the game. Lost or unfinished games
+// score nothing.
+func (g *Game) Score() int {
+	if !g.won {
+		return 0
+	}
+	return Score(g.cfg.Difficulty, g.attempts, g.hints, g.Elapsed())
+}
@@ -0,0 +1,296 @@
+package game
+
+import (
+	"errors"
+	"math"
+	"math/rand"
+	"testing"
+	"time"
+)
+
+// fixedClock makes g's clock advance by step every time it is read, so that
+// scores do not depend on how fast the test runs.
+func fixedClock(g *Game, step time.Duration) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	g.started = now
+	g.now = func() time.Time {
+		now = now.Add(step)
+		return now
+	}
+}
+
+func newGame(t *testing.T, cfg Config, secret int) *Game {
+	t.Helper()
+	g, err := NewWithSecret(cfg, secret)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return g
+}
+
+func TestGuessOutcomes(t *testing.T) {
+	cfg := Config{Min: 1, Max: 100}
+	tests := []struct {
+		guess int
+		want  Outcome
+	}{
+		{1, TooSmall},
+		{41, TooSmall},
+		{43, TooBig},
+		{100, TooBig},
+		{42, Correct},
+	}
+	for _, tt := range tests {
+		g := newGame(t, cfg, 42)
+		got, err := g.Guess(tt.guess)
+		if err != nil {
+			t.Fatalf("Guess(%d): %v", tt.guess, err)
+		}
+		if got != tt.want {
+			t.Errorf("Guess(%d) = %s, want %s", tt.guess, got, tt.want)
+		}
+		if g.Attempts() != 1 {
+			t.Errorf("Guess(%d): %d attempts, want 1", tt.guess, g.Attempts())
+		}
+		if over := tt.want == Correct; g.Over() != over || g.Won() != over {
+			t.Errorf("Guess(%d): over=%t won=%t, want both %t", tt.guess, g.Over(), g.Won(), over)
+		}
+	}
+}
+
+func TestGuessOutOfRange(t *testing.T) {
+	g := newGame(t, Config{Min: 1, Max: 10, MaxAttempts: 2}, 5)
+	for _, guess := range []int{0, 11, -3} {
+		if _, err := g.Guess(guess); !errors.Is(err, ErrOutOfRange) {
+			t.Errorf("Guess(%d) error = %v, want ErrOutOfRange", guess, err)
+		}
+	}
+	if g.Attempts() != 0 || g.Over() {
+		t.Errorf("out of range guesses used attempts: attempts=%d over=%t", g.Attempts(), g.Over())
+	}
+}
+
+func TestAttemptLimit(t *testing.T) {
+	g := newGame(t, Config{Min: 1, Max: 10, MaxAttempts: 3}, 5)
+	for i, guess := range []int{1, 2, 3} {
+		if g.Over() {
+			t.Fatalf("game over after %d of 3 attempts", i)
+		}
+		if left := g.AttemptsLeft(); left != 3-i {
+			t.Errorf("before guess %d: %d attempts left, want %d", i+1, left, 3-i)
+		}
+		g.Guess(guess)
+	}
+
+	if !g.Over() || g.Won() {
+		t.Fatalf("after 3 wrong guesses: over=%t won=%t, want over and lost", g.Over(), g.Won())
+	}
+	if g.AttemptsLeft() != 0 {
+		t.Errorf("AttemptsLeft = %d, want 0", g.AttemptsLeft())
+	}
+	if _, err := g.Guess(5); !errors.Is(err, ErrGameOver) {
+		t.Errorf("guess after the game ended: error = %v, want ErrGameOver", err)
+	}
+	if g.Score() != 0 {
+		t.Errorf("lost game scored %d, want 0", g.Score())
+	}
+}
+
+func TestUnlimitedAttempts(t *testing.T) {
+	g := newGame(t, Config{Min: 0, Max: 1000}, 999)
+	for guess := 0; guess < 999; guess++ {
+		g.Guess(guess)
+	}
+	if g.Over() {
+		t.Fatal("game with unlimited attempts ended before it was won")
+	}
+	if g.AttemptsLeft() != -1 {
+		t.Errorf("AttemptsLeft = %d, want -1", g.AttemptsLeft())
+	}
+	g.Guess(999)
+	if !g.Won() || g.Attempts() != 1000 {
+		t.Errorf("won=%t after %d attempts, want a win after 1000", g.Won(), g.Attempts())
+	}
+}
+
+func TestHint(t *testing.T) {
+	g := newGame(t, Config{Min: 1, Max: 100}, 42)
+
+	low, high := g.Hint()
+	if low != 1 || high != 50 {
+		t.Errorf("first hint = %d-%d, want 1-50", low, high)
+	}
+	// 30 is too small, leaving 31-50, and 42 is in the upper half of that.
+	g.Guess(30)
+	low, high = g.Hint()
+	if low != 41 || high != 50 {
+		t.Errorf("second hint = %d-%d, want 41-50", low, high)
+	}
+	if g.Hints() != 2 || g.Attempts() != 1 {
+		t.Errorf("hints=%d attempts=%d, want 2 and 1", g.Hints(), g.Attempts())
+	}
+
+	// Hints narrow down to the secret and then stop counting.
+	for i := 0; i < 10; i++ {
+		low, high = g.Hint()
+	}
+	if low != 42 || high != 42 {
+		t.Errorf("hints narrowed to %d-%d, want 42-42", low, high)
+	}
+	hints := g.Hints()
+	g.Hint()
+	if g.Hints() != hints {
+		t.Errorf("hint after the range was a single number still counted")
+	}
+}
+
+func TestQuit(t *testing.T) {
+	g := newGame(t, Config{Min: 1, Max: 10}, 5)
+	g.Quit()
+	if !g.Over() || g.Won() {
+		t.Errorf("after Quit: over=%t won=%t, want over and lost", g.Over(), g.Won())
+	}
+	if _, err := g.Guess(5); !errors.Is(err, ErrGameOver) {
+		t.Errorf("guess after Quit: error = %v, want ErrGameOver", err)
+	}
+}
+
+func TestScore(t *testing.T) {
+	tests := []struct {
+		name      string
+		d         Difficulty
+		attempts  int
+		hints     int
+		elapsed   time.Duration
+		wantScore int
+	}{
+		{"first try", Easy, 1, 0, 0, 1000},
+		{"normal doubles", Normal, 1, 0, 0, 2000},
+		{"hard triples", Hard, 1, 0, 0, 3000},
+		{"attempts cost", Easy, 5, 0, 0, 800},
+		{"hints cost", Easy, 1, 2, 0, 800},
+		{"seconds cost", Easy, 1, 0, 10 * time.Second, 980},
+		{"partial seconds are free", Easy, 1, 0, 1500 * time.Millisecond, 998},
+		{"never below the minimum", Hard, 100, 10, time.Hour, 30},
+	}
+	for _, tt := range tests {
+		if got := Score(tt.d, tt.attempts, tt.hints, tt.elapsed); got != tt.wantScore {
+			t.Errorf("%s: Score = %d, want %d", tt.name, got, tt.wantScore)
+		}
+	}
+}
+
+func TestGameScore(t *testing.T) {
+	g := newGame(t, Config{Difficulty: Normal, Min: 1, Max: 100, MaxAttempts: 10}, 42)
+	fixedClock(g, 3*time.Second)
+	g.Hint()
+	g.Guess(10)
+	g.Guess(42)
+
+	// Two guesses, one hint and the 3 seconds until the clock was read at
+	// the end of the game.
+	if want := (1000 - 50 - 100 - 6) * 2; g.Score() != want {
+		t.Errorf("Score = %d, want %d", g.Score(), want)
+	}
+}
+
+func TestNewIsReproducible(t *testing.T) {
+	cfg, err := Preset(HigherLower, Hard)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for seed := int64(0); seed < 20; seed++ {
+		a, err := New(cfg, seed)
+		if err != nil {
+			t.Fatal(err)
+		}
+		b, _ := New(cfg, seed)
+		if a.Secret() != b.Secret() {
+			t.Errorf("seed %d gave secrets %d and %d", seed, a.Secret(), b.Secret())
+		}
+		if a.Secret() < cfg.Min || a.Secret() > cfg.Max {
+			t.Errorf("seed %d gave secret %d outside %d-%d", seed, a.Secret(), cfg.Min, cfg.Max)
+		}
+	}
+}
+
+func TestPreset(t *testing.T) {
+	tests := []struct {
+		mode Mode
+		d    Difficulty
+		want Config
+	}{
+		{"", Easy, Config{Mode: HigherLower, Difficulty: Easy, Min: 0, Max: 10}},
+		{HigherLower, Normal, Config{Mode: HigherLower, Difficulty: Normal, Min: 1, Max: 100, MaxAttempts: 10}},
+		{HigherLower, Hard, Config{Mode: HigherLower, Difficulty: Hard, Min: 1, Max: 1000, MaxAttempts: 10}},
+		{BullsAndCows, Normal, Config{Mode: BullsAndCows, Difficulty: Normal, Digits: 4, Unique: true, MaxAttempts: 10}},
+	}
+	for _, tt := range tests {
+		got, err := Preset(tt.mode, tt.d)
+		if err != nil {
+			t.Errorf("Preset(%q, %q): %v", tt.mode, tt.d, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("Preset(%q, %q) = %+v, want %+v", tt.mode, tt.d, got, tt.want)
+		}
+	}
+
+	if _, err := Preset(HigherLower, "impossible"); err == nil {
+		t.Error("Preset accepted an unknown difficulty")
+	}
+	if _, err := Preset("roulette", Easy); err == nil {
+		t.Error("Preset accepted an unknown mode")
+	}
+}
+
+func TestValidate(t *testing.T) {
+	bad := []Config{
+		{Min: 10, Max: 10},
+		{Min: 10, Max: 1},
+		{Min: 1, Max: 10, MaxAttempts: -1},
+		{Mode: BullsAndCows, Digits: 0},
+		{Mode: BullsAndCows, Digits: 11},
+		{Mode: "roulette", Min: 1, Max: 10},
+		{Min: 0, Max: math.MaxInt},
+		{Min: -1, Max: math.MaxInt - 1},
+		{Min: math.MinInt, Max: -1},
+		{Min: math.MinInt, Max: math.MaxInt},
+	}
+	for _, cfg := range bad {
+		if err := cfg.Validate(); err == nil {
+			t.Errorf("Validate(%+v) = nil, want an error", cfg)
+		}
+		if _, err := NewWithSecret(cfg, 5); err == nil {
+			t.Errorf("NewWithSecret(%+v) = nil error, want one", cfg)
+		}
+	}
+}
+
+func TestWidestRange(t *testing.T) {
+	// Max-Min+1 is math.MaxInt, the most numbers a secret can be drawn
+	// from.
+	for _, cfg := range []Config{
+		{Min: 0, Max: math.MaxInt - 1},
+		{Min: 1, Max: math.MaxInt},
+		{Min: math.MinInt, Max: -2},
+		{Min: math.MinInt/2 + 1, Max: math.MaxInt / 2},
+	} {
+		if err := cfg.Validate(); err != nil {
+			t.Errorf("Validate(%+v) = %v", cfg, err)
+			continue
+		}
+		for seed := int64(0); seed < 100; seed++ {
+			g, err := New(cfg, seed)
+			if err != nil {
+				t.Fatalf("New(%+v, %d): %v", cfg, seed, err)
+			}
+			if g.secret < cfg.Min || g.secret > cfg.Max {
+				t.Fatalf("New(%+v, %d) drew %d", cfg, seed, g.secret)
+			}
+			if secret := cfg.RandomSecret(rand.New(rand.NewSource(seed))); secret != g.secret {
+				t.Fatalf("RandomSecret drew %d, New %d", secret, g.secret)
+			}
+		}
+	}
+}
@@ -0,0 +1,85 @@
+package game
+
+import "errors"
+
+// ErrQuit is returned by a Player that wants to give up the game.
+var ErrQuit = errors.New("player quit")
+
+// Player is anything that can play the game: a person at a console or a bot.
+// Every kind of player goes through Play, so they all follow the same rules.
+type Player interface {
+	// Start is called before the first guess of every game.
+	Start(cfg Config)
+
+	// Guess returns the next guess, or ErrQuit to abandon the game.
+	Guess() (int, error)
+
+	// Result reports how guess compared to the secret. If the game rejected
+	// the guess, err says why and the guess did not use up an attempt.
+	Result(guess int, outcome Outcome, err error)
+}
+
+// Play lets p play the HigherLower game g until the game is over.
+func Play(g *Game, p Player) error {
+	if g.Config().IsCode() {
+		return ErrWrongMode
+	}
+	p.Start(g.Config())
+
+	for !g.Over() {
+		guess, err := p.Guess()
+		if errors.Is(err, ErrQuit) {
+			g.Quit()
+			break
+		}
+		if err != nil {
+			return err
+		}
+
+		outcome, err := g.Guess(guess)
+		if err != nil && !errors.Is(err, ErrOutOfRange) {
+			return err
+		}
+		p.Result(guess, outcome, err)
+	}
+	return nil
+}
+
+// CodePlayer is a Player for BullsAndCows games, where guesses are codes.
+type CodePlayer interface {
+	// Start is called before the first guess of every game.
+	Start(cfg Config)
+
+	// GuessCode returns the next code, or ErrQuit to abandon the game.
+	GuessCode() (string, error)
+
+	// CodeResult reports the bulls and cows for guess. If the game rejected
+	// the guess, err says why and the guess did not use up an attempt.
+	CodeResult(guess string, f Feedback, err error)
+}
+
+// PlayCode lets p play the BullsAndCows game g until the game is over.
+func PlayCode(g *Game, p CodePlayer) error {
+	if !g.Config().IsCode() {
+		return ErrWrongMode
+	}
+	p.Start(g.Config())
+
+	for !g.Over() {
+		guess, err := p.GuessCode()
+		if errors.Is(err, ErrQuit) {
+			g.Quit()
+			break
+		}
+		if err != nil {
+			return err
+		}
+
+		f, err := g.GuessCode(guess)
+		if err != nil && !errors.Is(err, ErrInvalidCode) {
+			return err
+		}
+		p.CodeResult(guess, f, err)
+	}
+	return nil
+}
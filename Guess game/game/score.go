@@ -0,0 +1,22 @@
+package game
+
+import "time"
+
+const (
+	basePoints       = 1000
+	attemptPenalty   = 50
+	secondPenalty    = 2
+	hintPenalty      = 100
+	minimumWinPoints = 10
+)
+
+// Score calculates the points for a win. Every attempt after the first, every
+// hint and every second spent costs points, and the result is scaled by the
+// difficulty. A win is always worth at least a few points.
+func Score(d Difficulty, attempts, hints int, elapsed time.Duration) int {
+	points := basePoints - attemptPenalty*(attempts-1) - hintPenalty*hints - secondPenalty*int(elapsed/time.Second)
+	if points < minimumWinPoints {
+		points = minimumWinPoints
+	}
+	return points * d.multiplier()
+}
@@ -0,0 +1,138 @@
+package game
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+)
+
+// Move is one action taken in a game: a guess, a hint or quitting.
+type Move struct {
+	Action string `json:"action"`
+	Guess  int    `json:"guess,omitempty"`
+
+	// Code is the guess in a BullsAndCows game.
+	Code string `json:"code,omitempty"`
+
+	// Response is what the game answered, for example "too big" or the
+	// range given by a hint.
+	Response string `json:"response,omitempty"`
+}
+
+// Transcript is the full record of a game. Replaying it against a fresh game
+// with the same seed must give the same responses.
+type Transcript struct {
+	Config Config `json:"config"`
+
+	// Seed is omitted for games whose secret was given directly, in which
+	// case the replay uses Secret instead.
+	Seed   *int64 `json:"seed,omitempty"`
+	Secret int    `json:"secret"`
+	Code   string `json:"code,omitempty"`
+
+	Moves    []Move `json:"moves"`
+	Won      bool   `json:"won"`
+	Attempts int    `json:"attempts"`
+	Score    int    `json:"score"`
+
+	DurationMS int64 `json:"duration_ms"`
+}
+
+func (g *Game) record(m Move) {
+	g.moves = append(g.moves, m)
+}
+
+// Transcript returns the record of the game so far.
+func (g *Game) Transcript() Transcript {
+	return Transcript{
+		Config:     g.cfg,
+		Seed:       g.seed,
+		Secret:     g.secret,
+		Code:       g.code,
+		Moves:      append([]Move(nil), g.moves...),
+		Won:        g.won,
+		Attempts:   g.attempts,
+		Score:      g.Score(),
+		DurationMS: g.Elapsed().Milliseconds(),
+	}
+}
+
+// Save writes the transcript to path as JSON.
+func (t Transcript) Save(path string) error {
+	data, err := json.MarshalIndent(t, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(data, '\n'), 0o644)
+}
+
+// LoadTranscript reads a transcript written by Save.
+func LoadTranscript(path string) (Transcript, error) {
+	var t Transcript
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return t, err
+	}
+	if err := json.Unmarshal(data, &t); err != nil {
+		return t, fmt.Errorf("reading %s: %w", path, err)
+	}
+	return t, nil
+}
+
+// Replay plays the moves of t against a fresh game and checks that every
+// response, and the final result, matches what was recorded. It returns the
+// replayed game.
+func Replay(t Transcript) (*Game, error) {
+	var (
+		g   *Game
+		err error
+	)
+	switch {
+	case t.Seed != nil:
+		g, err = New(t.Config, *t.Seed)
+	case t.Config.IsCode():
+		g, err = NewWithCode(t.Config, t.Code)
+	default:
+		g, err = NewWithSecret(t.Config, t.Secret)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if g.secret != t.Secret || g.code != t.Code {
+		if t.Seed != nil {
+			return g, fmt.Errorf("seed %d gives a different secret than the transcript", *t.Seed)
+		}
+		return g, errors.New("secret does not match the transcript")
+	}
+
+	for i, m := range t.Moves {
+		switch m.Action {
+		case "guess":
+			if t.Config.IsCode() {
+				g.GuessCode(m.Code)
+			} else {
+				g.Guess(m.Guess)
+			}
+		case "hint":
+			g.Hint()
+		case "quit":
+			g.Quit()
+		default:
+			return g, fmt.Errorf("move %d: unknown action %q", i+1, m.Action)
+		}
+
+		if len(g.moves) != i+1 {
+			return g, fmt.Errorf("move %d: %s was not accepted by the game", i+1, m.Action)
+		}
+		if got := g.moves[i]; got != m {
+			return g, fmt.Errorf("move %d: %s got %q, transcript says %q", i+1, m.Action, got.Response, m.Response)
+		}
+	}
+
+	if g.won != t.Won || g.attempts != t.Attempts {
+		return g, fmt.Errorf("replay ended with won=%t after %d attempts, transcript says won=%t after %d attempts", g.won, g.attempts, t.Won, t.Attempts)
+	}
+	return g, nil
+}
@@ -0,0 +1,93 @@
+package game
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestTranscriptRoundTrip(t *testing.T) {
+	cfg, _ := Preset(HigherLower, Normal)
+	g, err := New(cfg, 12)
+	if err != nil {
+		t.Fatal(err)
+	}
+	g.Guess(0)
+	g.Hint()
+	g.Guess(50)
+	g.Guess(g.Secret())
+
+	path := filepath.Join(t.TempDir(), "game.json")
+	if err := g.Transcript().Save(path); err != nil {
+		t.Fatal(err)
+	}
+	loaded, err := LoadTranscript(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	replayed, err := Replay(loaded)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !replayed.Won() || replayed.Attempts() != g.Attempts() || replayed.Hints() != 1 {
+		t.Errorf("replay won=%t after %d attempts and %d hints, want a win after %d and 1", replayed.Won(), replayed.Attempts(), replayed.Hints(), g.Attempts())
+	}
+}
+
+func TestReplayCode(t *testing.T) {
+	cfg, _ := Preset(BullsAndCows, Normal)
+	g, err := NewWithCode(cfg, "0123")
+	if err != nil {
+		t.Fatal(err)
+	}
+	g.GuessCode("99")
+	g.GuessCode("3210")
+	g.GuessCode("0123")
+
+	if _, err := Replay(g.Transcript()); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestReplayMismatch(t *testing.T) {
+	cfg, _ := Preset(HigherLower, Normal)
+	seeded, _ := New(cfg, 12)
+	seeded.Guess(1)
+	seeded.Guess(100)
+
+	given, _ := NewWithSecret(cfg, 40)
+	given.Guess(50)
+
+	code, _ := Preset(BullsAndCows, Normal)
+
+	tests := []struct {
+		name   string
+		edit   func(*Transcript)
+		source *Game
+		want   string
+	}{
+		{"response changed", func(tr *Transcript) { tr.Moves[0].Response = "correct" }, seeded, "move 1"},
+		{"unknown action", func(tr *Transcript) { tr.Moves[1].Action = "cheat" }, seeded, `unknown action "cheat"`},
+		{"result changed", func(tr *Transcript) { tr.Won = true }, seeded, "won=true"},
+		{"seed gives another secret", func(tr *Transcript) { tr.Secret++ }, seeded, "seed 12 gives a different secret"},
+
+		// Without a seed a transcript that does not hang together must be
+		// reported, not crash the replay.
+		{"code in a number game", func(tr *Transcript) { tr.Code = "1234" }, given, "secret does not match"},
+		{"number in a code game", func(tr *Transcript) {
+			tr.Config, tr.Code, tr.Secret, tr.Moves, tr.Attempts = code, "1234", 7, nil, 0
+		}, given, "secret does not match"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tr := tt.source.Transcript()
+			tr.Moves = append([]Move(nil), tr.Moves...)
+			tt.edit(&tr)
+
+			_, err := Replay(tr)
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("Replay error = %v, want one mentioning %q", err, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,93 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+
+	"github.com/TheInvincibleRalph/Guessing-game.git/grading"
+)
+
+// Create a function to grade a CSV file of student scores
+func grade(args []string) {
+	flags := flag.NewFlagSet("grade", flag.ExitOnError)
+	bandsFile := flags.String("bands", "", "JSON file with the grade bands (the built-in A-D bands if empty)")
+	in := flags.String("in", "", "CSV file of scores, with a header row (stdin if empty)")
+	out := flags.String("out", "", "file to write the report to (stdout if empty)")
+	format := flags.String("format", "json", "report format: json or csv")
+	nameColumn := flags.String("name-column", "name", "column holding the student name")
+	scoreColumn := flags.String("score-column", "score", "column holding the score")
+	flags.Parse(args)
+
+	if *format != "json" && *format != "csv" {
+		log.Fatalf("Unknown format %q, expected json or csv", *format)
+	}
+
+	bands := grading.DefaultBands
+	if *bandsFile != "" {
+		var err error
+		bands, err = grading.LoadBands(*bandsFile)
+		if err != nil {
+			log.Fatal("Invalid grade bands: ", err)
+		}
+	}
+
+	var input io.Reader = os.Stdin
+	if *in != "" {
+		file, err := os.Open(*in)
+		if err != nil {
+			log.Fatal(err)
+		}
+		defer file.Close()
+		input = file
+	}
+
+	report, err := grading.Grade(input, bands, *nameColumn, *scoreColumn)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	var output io.Writer = os.Stdout
+	var file *os.File
+	if *out != "" {
+		file, err = os.Create(*out)
+		if err != nil {
+			log.Fatal(err)
+		}
+		output = file
+	}
+
+	if *format == "csv" {
+		err = report.WriteCSV(output)
+	} else {
+		err = report.WriteJSON(output)
+	}
+	if err != nil {
+		log.Fatal("Failed to write the report: ", err)
+	}
+	//closing the file is when a failed write may show up
+	if file != nil {
+		if err := file.Close(); err != nil {
+			log.Fatal("Failed to write the report: ", err)
+		}
+	}
+
+	//The CSV report only has the students, so print the rest alongside it
+	if *format == "csv" || *out != "" {
+		printGradeSummary(report)
+	}
+}
+
+// Create a function to print the grade distribution and summary statistics
+func printGradeSummary(report *grading.Report) {
+	s := report.Summary
+	fmt.Fprintf(os.Stderr, "Graded %d students: mean %.2f, median %.2f, stddev %.2f, min %g, max %g\n", s.Count, s.Mean, s.Median, s.StdDev, s.Min, s.Max)
+	for _, c := range report.Distribution {
+		fmt.Fprintf(os.Stderr, "  %-3s %d\n", c.Grade, c.Count)
+	}
+	for _, e := range report.Errors {
+		fmt.Fprintln(os.Stderr, "Skipped", e)
+	}
+}
@@ -0,0 +1,101 @@
+package grading
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+)
+
+// Band gives Grade to every score from Min up to, but not including, Max.
+// The highest band also includes its Max so that a perfect score is graded.
+type Band struct {
+	Grade string  `json:"grade"`
+	Min   float64 `json:"min"`
+	Max   float64 `json:"max"`
+}
+
+// Bands is a validated set of bands, ordered from the lowest scores up.
+type Bands []Band
+
+// DefaultBands are the bands of the original grade() function with the
+// overlaps and the gap at 49 fixed.
+var DefaultBands = Bands{
+	{Grade: "D", Min: 0, Max: 50},
+	{Grade: "C", Min: 50, Max: 60},
+	{Grade: "B", Min: 60, Max: 70},
+	{Grade: "A", Min: 70, Max: 100},
+}
+
+// ErrOutOfRange is returned for scores that no band covers.
+var ErrOutOfRange = errors.New("score out of range")
+
+// LoadBands reads bands from a JSON file of the form
+//
+//	{"bands": [{"grade": "D", "min": 0, "max": 50}, ...]}
+//
+// and validates them.
+func LoadBands(path string) (Bands, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var file struct {
+		Bands []Band `json:"bands"`
+	}
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("reading %s: %w", path, err)
+	}
+	return NewBands(file.Bands)
+}
+
+// NewBands sorts bands and checks that they cover one continuous range of
+// scores, without overlapping or leaving gaps between them.
+func NewBands(bands []Band) (Bands, error) {
+	if len(bands) == 0 {
+		return nil, errors.New("no bands defined")
+	}
+
+	sorted := append(Bands(nil), bands...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].Min < sorted[j].Min })
+
+	seen := map[string]bool{}
+	for i, b := range sorted {
+		if b.Grade == "" {
+			return nil, fmt.Errorf("band %g-%g has no grade", b.Min, b.Max)
+		}
+		if seen[b.Grade] {
+			return nil, fmt.Errorf("grade %s is defined more than once", b.Grade)
+		}
+		seen[b.Grade] = true
+
+		if b.Min >= b.Max {
+			return nil, fmt.Errorf("band %s: min %g must be below max %g", b.Grade, b.Min, b.Max)
+		}
+		if i == 0 {
+			continue
+		}
+
+		prev := sorted[i-1]
+		switch {
+		case b.Min < prev.Max:
+			return nil, fmt.Errorf("bands %s and %s overlap between %g and %g", prev.Grade, b.Grade, b.Min, prev.Max)
+		case b.Min > prev.Max:
+			return nil, fmt.Errorf("gap between bands %s and %s from %g to %g", prev.Grade, b.Grade, prev.Max, b.Min)
+		}
+	}
+	return sorted, nil
+}
+
+// Grade returns the grade for score.
+func (b Bands) Grade(score float64) (string, error) {
+	for i, band := range b {
+		last := i == len(b)-1
+		if score >= band.Min && (score < band.Max || (last && score == band.Max)) {
+			return band.Grade, nil
+		}
+	}
+	return "", fmt.Errorf("%w: %g is not between %g and %g", ErrOutOfRange, score, b[0].Min, b[len(b)-1].Max)
+}
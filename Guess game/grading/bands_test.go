@@ -0,0 +1,103 @@
+package grading
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestNewBands(t *testing.T) {
+	bands, err := NewBands([]Band{
+		{Grade: "A", Min: 70, Max: 100},
+		{Grade: "D", Min: 0, Max: 50},
+		{Grade: "B", Min: 60, Max: 70},
+		{Grade: "C", Min: 50, Max: 60},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var grades []string
+	for _, b := range bands {
+		grades = append(grades, b.Grade)
+	}
+	if got := strings.Join(grades, ""); got != "DCBA" {
+		t.Errorf("NewBands sorted the bands as %s, want DCBA", got)
+	}
+}
+
+func TestNewBandsErrors(t *testing.T) {
+	tests := []struct {
+		name  string
+		bands []Band
+		want  string
+	}{
+		{"none", nil, "no bands"},
+		{"overlap", []Band{{Grade: "B", Min: 0, Max: 55}, {Grade: "A", Min: 50, Max: 100}}, "overlap between 50 and 55"},
+		{"contained", []Band{{Grade: "B", Min: 0, Max: 100}, {Grade: "A", Min: 50, Max: 60}}, "overlap"},
+		{"same min", []Band{{Grade: "B", Min: 0, Max: 50}, {Grade: "A", Min: 0, Max: 100}}, "overlap"},
+		{"gap", []Band{{Grade: "B", Min: 0, Max: 49}, {Grade: "A", Min: 50, Max: 100}}, "gap between bands B and A from 49 to 50"},
+		{"empty band", []Band{{Grade: "A", Min: 50, Max: 50}}, "min 50 must be below max 50"},
+		{"backwards band", []Band{{Grade: "A", Min: 60, Max: 50}}, "must be below"},
+		{"no grade", []Band{{Min: 0, Max: 100}}, "has no grade"},
+		{"repeated grade", []Band{{Grade: "A", Min: 0, Max: 50}, {Grade: "A", Min: 50, Max: 100}}, "more than once"},
+	}
+	for _, tt := range tests {
+		_, err := NewBands(tt.bands)
+		if err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("%s: NewBands(%v) = %v, want an error mentioning %q", tt.name, tt.bands, err, tt.want)
+		}
+	}
+}
+
+func TestGradeBands(t *testing.T) {
+	tests := []struct {
+		score float64
+		want  string
+	}{
+		{0, "D"},
+		{49.99, "D"},
+		{50, "C"},
+		{59.5, "C"},
+		{60, "B"},
+		{70, "A"},
+		{99.9, "A"},
+		// The top band includes its max, so a perfect score is graded.
+		{100, "A"},
+	}
+	for _, tt := range tests {
+		if got, err := DefaultBands.Grade(tt.score); err != nil || got != tt.want {
+			t.Errorf("Grade(%g) = %q, %v, want %q", tt.score, got, err, tt.want)
+		}
+	}
+
+	for _, score := range []float64{-1, -0.01, 100.01, 1000} {
+		if got, err := DefaultBands.Grade(score); !errors.Is(err, ErrOutOfRange) {
+			t.Errorf("Grade(%g) = %q, %v, want ErrOutOfRange", score, got, err)
+		}
+	}
+}
+
+func TestLoadBands(t *testing.T) {
+	bands, err := LoadBands("../bands.example.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(bands) != len(DefaultBands) {
+		t.Fatalf("LoadBands read %v, want %v", bands, DefaultBands)
+	}
+	for i := range bands {
+		if bands[i] != DefaultBands[i] {
+			t.Errorf("band %d = %+v, want %+v", i, bands[i], DefaultBands[i])
+		}
+	}
+
+	path := filepath.Join(t.TempDir(), "bands.json")
+	if err := os.WriteFile(path, []byte(`{"bands": [{"grade": "A", "min": 0, "max": "100"}]}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LoadBands(path); err == nil {
+		t.Error("LoadBands accepted a max given as a string")
+	}
+}
@@ -0,0 +1,184 @@
+package grading
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Student is one graded row of the input.
+type Student struct {
+	Name  string  `json:"name"`
+	Score float64 `json:"score"`
+	Grade string  `json:"grade"`
+}
+
+// Count is how many students got a grade.
+type Count struct {
+	Grade string `json:"grade"`
+	Count int    `json:"count"`
+}
+
+// Summary holds statistics over the graded scores.
+type Summary struct {
+	Count  int     `json:"count"`
+	Mean   float64 `json:"mean"`
+	Median float64 `json:"median"`
+	StdDev float64 `json:"stddev"`
+	Min    float64 `json:"min"`
+	Max    float64 `json:"max"`
+}
+
+// Report is the result of grading a batch of scores.
+type Report struct {
+	Students []Student `json:"students"`
+
+	// Distribution lists every grade from the lowest band up, including
+	// grades nobody got.
+	Distribution []Count `json:"distribution"`
+	Summary      Summary `json:"summary"`
+
+	// Errors describes rows that could not be graded. They are left out of
+	// the students, the distribution and the summary.
+	Errors []string `json:"errors,omitempty"`
+}
+
+// Grade reads a CSV of scores from r and grades every row. The first row is a
+// header naming the columns; nameColumn and scoreColumn pick the ones to use.
+// Rows that cannot be read or graded are listed in the report's Errors.
+func Grade(r io.Reader, bands Bands, nameColumn, scoreColumn string) (*Report, error) {
+	reader := csv.NewReader(r)
+	reader.TrimLeadingSpace = true
+	// Rows may have any number of fields; the ones too short to hold a
+	// name and a score are reported below.
+	reader.FieldsPerRecord = -1
+
+	header, err := reader.Read()
+	if errors.Is(err, io.EOF) {
+		return nil, errors.New("input is empty")
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	nameIdx, scoreIdx := -1, -1
+	for i, column := range header {
+		switch strings.TrimSpace(column) {
+		case nameColumn:
+			nameIdx = i
+		case scoreColumn:
+			scoreIdx = i
+		}
+	}
+	if nameIdx < 0 || scoreIdx < 0 {
+		return nil, fmt.Errorf("input needs %q and %q columns, found %v", nameColumn, scoreColumn, header)
+	}
+
+	report := &Report{}
+	counts := map[string]int{}
+	var scores []float64
+
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		var parseErr *csv.ParseError
+		if errors.As(err, &parseErr) {
+			report.Errors = append(report.Errors, fmt.Sprintf("line %d: %s", parseErr.StartLine, parseErr.Err))
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		line, _ := reader.FieldPos(0)
+		if len(record) <= max(nameIdx, scoreIdx) {
+			report.Errors = append(report.Errors, fmt.Sprintf("line %d: %d fields, want at least %d", line, len(record), max(nameIdx, scoreIdx)+1))
+			continue
+		}
+
+		name := strings.TrimSpace(record[nameIdx])
+		score, err := strconv.ParseFloat(strings.TrimSpace(record[scoreIdx]), 64)
+		if err != nil {
+			report.Errors = append(report.Errors, fmt.Sprintf("line %d: %s: invalid score %q", line, name, record[scoreIdx]))
+			continue
+		}
+
+		grade, err := bands.Grade(score)
+		if err != nil {
+			report.Errors = append(report.Errors, fmt.Sprintf("line %d: %s: %s", line, name, err))
+			continue
+		}
+
+		report.Students = append(report.Students, Student{Name: name, Score: score, Grade: grade})
+		counts[grade]++
+		scores = append(scores, score)
+	}
+
+	for _, b := range bands {
+		report.Distribution = append(report.Distribution, Count{Grade: b.Grade, Count: counts[b.Grade]})
+	}
+	report.Summary = summarise(scores)
+	return report, nil
+}
+
+func summarise(scores []float64) Summary {
+	s := Summary{Count: len(scores)}
+	if len(scores) == 0 {
+		return s
+	}
+
+	sorted := append([]float64(nil), scores...)
+	sort.Float64s(sorted)
+	s.Min, s.Max = sorted[0], sorted[len(sorted)-1]
+
+	var sum float64
+	for _, v := range sorted {
+		sum += v
+	}
+	s.Mean = sum / float64(len(sorted))
+
+	mid := len(sorted) / 2
+	if len(sorted)%2 == 0 {
+		s.Median = (sorted[mid-1] + sorted[mid]) / 2
+	} else {
+		s.Median = sorted[mid]
+	}
+
+	// Population standard deviation, since the batch is the whole class.
+	var squares float64
+	for _, v := range sorted {
+		squares += (v - s.Mean) * (v - s.Mean)
+	}
+	s.StdDev = math.Sqrt(squares / float64(len(sorted)))
+	return s
+}
+
+// WriteJSON writes the whole report as JSON.
+func (r *Report) WriteJSON(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(r)
+}
+
+// WriteCSV writes one row per graded student.
+func (r *Report) WriteCSV(w io.Writer) error {
+	writer := csv.NewWriter(w)
+	if err := writer.Write([]string{"name", "score", "grade"}); err != nil {
+		return err
+	}
+	for _, s := range r.Students {
+		if err := writer.Write([]string{s.Name, strconv.FormatFloat(s.Score, 'f', -1, 64), s.Grade}); err != nil {
+			return err
+		}
+	}
+	writer.Flush()
+	return writer.Error()
+}
@@ -0,0 +1,120 @@
+package grading
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"testing"
+)
+
+func TestGrade(t *testing.T) {
+	input := `name,score
+Ada, 95
+Grace,70
+Linus,65
+Ken,50
+Dennis,30
+Barbara,100
+`
+	report, err := Grade(strings.NewReader(input), DefaultBands, "name", "score")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got := fmt.Sprint(report.Distribution); got != "[{D 1} {C 1} {B 1} {A 3}]" {
+		t.Errorf("distribution = %s, want [{D 1} {C 1} {B 1} {A 3}]", got)
+	}
+	if len(report.Students) != 6 || report.Students[0] != (Student{"Ada", 95, "A"}) {
+		t.Errorf("students = %v", report.Students)
+	}
+
+	s := report.Summary
+	// The scores are 30, 50, 65, 70, 95 and 100.
+	want := Summary{Count: 6, Mean: 410.0 / 6, Median: 67.5, Min: 30, Max: 100}
+	var squares float64
+	for _, v := range []float64{30, 50, 65, 70, 95, 100} {
+		squares += (v - want.Mean) * (v - want.Mean)
+	}
+	want.StdDev = math.Sqrt(squares / 6)
+	if s.Count != want.Count || s.Min != want.Min || s.Max != want.Max || s.Median != want.Median ||
+		math.Abs(s.Mean-want.Mean) > 1e-9 || math.Abs(s.StdDev-want.StdDev) > 1e-9 {
+		t.Errorf("summary = %+v, want %+v", s, want)
+	}
+}
+
+func TestSummarise(t *testing.T) {
+	tests := []struct {
+		scores []float64
+		want   Summary
+	}{
+		{nil, Summary{}},
+		{[]float64{42}, Summary{Count: 1, Mean: 42, Median: 42, Min: 42, Max: 42}},
+		{[]float64{2, 4, 4, 4, 5, 5, 7, 9}, Summary{Count: 8, Mean: 5, Median: 4.5, StdDev: 2, Min: 2, Max: 9}},
+		{[]float64{9, 1, 5}, Summary{Count: 3, Mean: 5, Median: 5, StdDev: math.Sqrt(32.0 / 3), Min: 1, Max: 9}},
+	}
+	for _, tt := range tests {
+		if got := summarise(tt.scores); got != tt.want {
+			t.Errorf("summarise(%v) = %+v, want %+v", tt.scores, got, tt.want)
+		}
+	}
+}
+
+func TestGradeSkipsBadRows(t *testing.T) {
+	input := `id,score,name
+1,80,Ada
+2,abc,Grace
+3,120,Linus
+4,55
+5,"60,Ken
+6,40,Dennis
+`
+	report, err := Grade(strings.NewReader(input), DefaultBands, "name", "score")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var graded []string
+	for _, s := range report.Students {
+		graded = append(graded, s.Name)
+	}
+	if got := strings.Join(graded, ","); got != "Ada" {
+		t.Errorf("graded %s, want Ada", got)
+	}
+	want := []string{
+		`line 3: Grace: invalid score "abc"`,
+		"line 4: Linus: score out of range: 120 is not between 0 and 100",
+		"line 5: 2 fields, want at least 3",
+		`line 6: extraneous or missing " in quoted-field`,
+	}
+	if fmt.Sprint(report.Errors) != fmt.Sprint(want) {
+		t.Errorf("errors =\n%q\nwant\n%q", report.Errors, want)
+	}
+}
+
+func TestGradeErrors(t *testing.T) {
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{"", "input is empty"},
+		{"student,mark\nAda,90\n", `needs "name" and "score" columns`},
+	}
+	for _, tt := range tests {
+		_, err := Grade(strings.NewReader(tt.input), DefaultBands, "name", "score")
+		if err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("Grade(%q) = %v, want an error mentioning %q", tt.input, err, tt.want)
+		}
+	}
+}
+
+func TestWriteCSV(t *testing.T) {
+	report := &Report{Students: []Student{{"Ada", 95, "A"}, {"Grace, Hopper", 49.5, "D"}}}
+	var out strings.Builder
+	if err := report.WriteCSV(&out); err != nil {
+		t.Fatal(err)
+	}
+	want := "name,score,grade\nAda,95,A\n\"Grace, Hopper\",49.5,D\n"
+	if out.String() != want {
+		t.Errorf("WriteCSV wrote %q, want %q", out.String(), want)
+	}
+}
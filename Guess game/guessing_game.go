@@ -1,75 +1,150 @@
 package main
 
 import (
+	"flag"
 	"fmt"
-	"math/rand"
+	"log"
+	"os"
 	"time"
+
+	"github.com/TheInvincibleRalph/Guessing-game.git/game"
+	"github.com/TheInvincibleRalph/Guessing-game.git/leaderboard"
 )
 
+const usage = `Usage: guess [command] [flags]
+
+Commands:
+  play          play a game (the default)
+  stats         show statistics for a player or for everyone
+  leaderboard   show the best scores
+  simulate      let a bot play many games and show how many attempts it needed
+  server        host a multiplayer game over TCP
+  api           serve games as a JSON API over HTTP
+  replay        replay a recorded game and check it still plays out the same
+  grade         grade a CSV file of student scores
+
+Run "guess <command> -h" for the flags of a command.
+`
+
 func main() {
+	command, args := "play", os.Args[1:]
+	if len(args) > 0 && args[0] != "" && args[0][0] != '-' {
+		command, args = args[0], args[1:]
+	}
 
-	//Create secret number
-	secret := getRandomNumber()
+	switch command {
+	case "play":
+		play(args)
+	case "stats":
+		stats(args)
+	case "leaderboard":
+		showLeaderboard(args)
+	case "simulate":
+		simulate(args)
+	case "server":
+		server(args)
+	case "api":
+		serveAPI(args)
+	case "replay":
+		replay(args)
+	case "grade":
+		grade(args)
+	default:
+		fmt.Fprint(os.Stderr, usage)
+		os.Exit(2)
+	}
+}
+
+// Create a function to register the flags that choose the rules of a game.
+// The returned function builds the config once the flags are parsed.
+func configFlags(flags *flag.FlagSet) func() game.Config {
+	mode := flags.String("mode", string(game.HigherLower), fmt.Sprintf("game to play: %s or %s", game.HigherLower, game.BullsAndCows))
+	difficulty := flags.String("difficulty", "normal", "difficulty preset: easy, normal or hard")
+	min := flags.Int("min", 0, "smallest possible secret (overrides the preset)")
+	max := flags.Int("max", 0, "largest possible secret (overrides the preset)")
+	digits := flags.Int("digits", 0, "length of the secret code in bulls-and-cows (overrides the preset)")
+	unique := flags.Bool("unique", false, "whether the digits of the code are all different (overrides the preset)")
+	attempts := flags.Int("attempts", 0, "number of guesses allowed, 0 for unlimited (overrides the preset)")
 
-	for matching := false; !matching; {
+	return func() game.Config {
+		cfg, err := game.Preset(game.Mode(*mode), game.Difficulty(*difficulty))
+		if err != nil {
+			log.Fatal(err)
+		}
 
-		//Get user input
-		guess := getUserInput()
+		//Flags given on the command line take precedence over the preset
+		flags.Visit(func(f *flag.Flag) {
+			switch f.Name {
+			case "min":
+				cfg.Min = *min
+			case "max":
+				cfg.Max = *max
+			case "digits":
+				cfg.Digits = *digits
+			case "unique":
+				cfg.Unique = *unique
+			case "attempts":
+				cfg.MaxAttempts = *attempts
+			}
+		})
 
-		//Make comparison (secret vs guess)
-		matching = isMatching(secret, guess)
+		if err := cfg.Validate(); err != nil {
+			log.Fatal(err)
+		}
+		return cfg
 	}
 }
 
-// Create a function to make comparison between the secret and guess number
-func isMatching(secret, guess int) bool {
-	if guess > secret {
-		fmt.Println("Your guess is too big")
-		return false
-	} else if guess < secret {
-		fmt.Println("Your guess is too small")
-		return false
-	} else {
-		fmt.Println("YOU GOT IT!")
-		return true
+// Create a function to register the -seed flag. The returned function gives
+// the seed to use, picking one from the clock when none was given.
+func seedFlag(flags *flag.FlagSet) func() int64 {
+	seed := flags.Int64("seed", 0, "seed for the random secret, 0 to pick one from the clock")
+
+	return func() int64 {
+		if *seed == 0 {
+			return time.Now().UnixNano()
+		}
+		return *seed
 	}
 }
 
-// Create a function to get user input
-func getUserInput() int {
-	fmt.Print("Please print your guess: ")
-	var input int
-	_, err := fmt.Scan(&input)
+func play(args []string) {
+	flags := flag.NewFlagSet("play", flag.ExitOnError)
+	config := configFlags(flags)
+	name := flags.String("name", "", "player name for the leaderboard (asked for if empty)")
+	file := flags.String("file", leaderboard.DefaultPath(), "file the results are saved to")
+	seed := seedFlag(flags)
+	record := flags.String("record", "", "write a transcript of the game to this file")
+	flags.Parse(args)
+	cfg := config()
+
+	console := game.NewConsole(os.Stdin, os.Stdout)
+	player := *name
+	if player == "" {
+		player = console.AskName()
+	}
+
+	//Create secret number
+	g, err := game.New(cfg, seed())
 	if err != nil {
-		fmt.Println("Failed to parse your input")
-	} else {
-		fmt.Println("You guessed:", input)
+		log.Fatal(err)
 	}
-	return input
-}
 
-// Create a function to generate the random number
-func getRandomNumber() int {
-	rand.Seed(time.Now().Unix())
-	return rand.Int() % 11
-}
+	//Loop the input until correct, out of attempts or the player quits
+	if err := console.Play(g); err != nil {
+		log.Fatal(err)
+	}
+
+	//Keep a transcript that can be replayed with "guess replay"
+	if *record != "" {
+		if err := g.Transcript().Save(*record); err != nil {
+			log.Fatal("Failed to save the transcript: ", err)
+		}
+		fmt.Println("Transcript saved to", *record)
+	}
 
-// func grade() {
-// 	score := 120
-
-// 	if score < 49 {
-// 		fmt.Println("Your grade  is D")
-// 	} else if score > 49 && score <= 59 {
-// 		fmt.Println("Your grade  is C")
-// 	} else if score > 59 && score <= 69 {
-// 		fmt.Println("Your grade  is B")
-// 	} else if score > 69 && score <= 100 {
-// 		fmt.Println("Your grade  is A")
-// 	} else {
-// 		fmt.Println("Score out of range")
-// 	}
-// }
-
-// func main() {
-// 	grade()
-// }
+	//Save the result so it shows up in stats and the leaderboard
+	if err := leaderboard.Open(*file).Add(leaderboard.NewResult(player, g)); err != nil {
+		log.Fatal("Failed to save the result: ", err)
+	}
+}
@@ -0,0 +1,90 @@
+package leaderboard
+
+import (
+	"sort"
+
+	"github.com/TheInvincibleRalph/Guessing-game.git/game"
+)
+
+// Stats summarises a set of results.
+type Stats struct {
+	Games           int
+	Wins            int
+	BestScore       int
+	AverageAttempts float64
+
+	// Histogram maps a number of attempts to how many games took that many.
+	Histogram map[int]int
+}
+
+// WinRate returns the fraction of games that were won.
+func (s Stats) WinRate() float64 {
+	if s.Games == 0 {
+		return 0
+	}
+	return float64(s.Wins) / float64(s.Games)
+}
+
+// Summarise computes the stats for the results of player, or for every
+// player when player is empty.
+func Summarise(results []Result, player string) Stats {
+	stats := Stats{Histogram: map[int]int{}}
+
+	var attempts int
+	for _, r := range results {
+		if player != "" && r.Player != player {
+			continue
+		}
+
+		stats.Games++
+		attempts += r.Attempts
+		stats.Histogram[r.Attempts]++
+		if r.Won {
+			stats.Wins++
+		}
+		if r.Score > stats.BestScore {
+			stats.BestScore = r.Score
+		}
+	}
+
+	if stats.Games > 0 {
+		stats.AverageAttempts = float64(attempts) / float64(stats.Games)
+	}
+	return stats
+}
+
+// Top returns the n best winning results, highest score first. Only results
+// of the given mode and difficulty are included, unless they are empty. Ties
+// go to the player who got there first.
+func Top(results []Result, n int, mode game.Mode, difficulty game.Difficulty) []Result {
+	var top []Result
+	for _, r := range results {
+		if !r.Won || (difficulty != "" && r.Difficulty != difficulty) {
+			continue
+		}
+		if mode != "" && r.GameMode() != mode {
+			continue
+		}
+		top = append(top, r)
+	}
+
+	sort.SliceStable(top, func(i, j int) bool {
+		if top[i].Score != top[j].Score {
+			return top[i].Score > top[j].Score
+		}
+		return top[i].PlayedAt.Before(top[j].PlayedAt)
+	})
+
+	if n > 0 && len(top) > n {
+		top = top[:n]
+	}
+	return top
+}
+
+// GameMode returns the mode the result was played in.
+func (r Result) GameMode() game.Mode {
+	if r.Mode == "" {
+		return game.HigherLower
+	}
+	return r.Mode
+}
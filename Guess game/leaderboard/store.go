@@ -0,0 +1,201 @@
+package leaderboard
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/TheInvincibleRalph/Guessing-game.git/game"
+)
+
+const (
+	// lockTimeout is how long Add waits for another process to release the
+	// lock before giving up.
+	lockTimeout = 5 * time.Second
+
+	// staleLockAge is how old a lock must be before it is considered
+	// abandoned by a crashed process. Writes take milliseconds, so this is
+	// far longer than any live writer holds the lock, and longer than
+	// lockTimeout so that a waiter gives up before it would break the lock
+	// of a slow writer.
+	staleLockAge = time.Minute
+)
+
+// Result is the record of one finished game.
+type Result struct {
+	Player string `json:"player"`
+
+	// Mode is empty for results saved before there was more than one mode,
+	// which were all HigherLower games.
+	Mode       game.Mode       `json:"mode,omitempty"`
+	Difficulty game.Difficulty `json:"difficulty"`
+	Won        bool            `json:"won"`
+	Attempts   int             `json:"attempts"`
+	Hints      int             `json:"hints"`
+	Score      int             `json:"score"`
+	DurationMS int64           `json:"duration_ms"`
+	PlayedAt   time.Time       `json:"played_at"`
+}
+
+// NewResult records the outcome of a finished game for player.
+func NewResult(player string, g *game.Game) Result {
+	return Result{
+		Player:     player,
+		Mode:       g.Config().Mode,
+		Difficulty: g.Config().Difficulty,
+		Won:        g.Won(),
+		Attempts:   g.Attempts(),
+		Hints:      g.Hints(),
+		Score:      g.Score(),
+		DurationMS: g.Elapsed().Milliseconds(),
+		PlayedAt:   time.Now().UTC(),
+	}
+}
+
+// Store keeps game results in a JSON file. Several games may finish at the
+// same time, so every write takes a lock file and replaces the results file
+// atomically.
+type Store struct {
+	path string
+}
+
+// DefaultPath returns the results file in the user's home directory.
+func DefaultPath() string {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "guessing-game.json"
+	}
+	return filepath.Join(home, ".guessing-game.json")
+}
+
+// Open returns a store backed by the file at path. The file is created on
+// the first Add.
+func Open(path string) *Store {
+	return &Store{path: path}
+}
+
+// Results returns every result saved so far, oldest first.
+func (s *Store) Results() ([]Result, error) {
+	data, err := os.ReadFile(s.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var results []Result
+	if err := json.Unmarshal(data, &results); err != nil {
+		return nil, fmt.Errorf("reading %s: %w", s.path, err)
+	}
+	return results, nil
+}
+
+// Add appends r to the store.
+func (s *Store) Add(r Result) error {
+	unlock, err := s.lock()
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	results, err := s.Results()
+	if err != nil {
+		return err
+	}
+	results = append(results, r)
+
+	data, err := json.MarshalIndent(results, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	// Write to a temporary file first so readers never see a half-written
+	// results file.
+	tmp, err := os.CreateTemp(filepath.Dir(s.path), filepath.Base(s.path)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+	return os.Rename(tmp.Name(), s.path)
+}
+
+// lock creates the lock file next to the results file, waiting while another
+// process holds it. The returned function releases the lock.
+//
+// The lock file holds the process ID of its holder and a random nonce, so
+// that a lock is only ever removed by whoever saw exactly that lock: its
+// holder, or a waiter that found it abandoned.
+func (s *Store) lock() (func(), error) {
+	path := s.path + ".lock"
+	deadline := time.Now().Add(lockTimeout)
+
+	nonce := make([]byte, 8)
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+	token := []byte(fmt.Sprintf("%d %x\n", os.Getpid(), nonce))
+
+	for {
+		err := createLock(path, token)
+		if err == nil {
+			return func() { removeLock(path, token) }, nil
+		}
+		if !errors.Is(err, os.ErrExist) {
+			return nil, err
+		}
+
+		// Remove locks left behind by a process that died holding them. The
+		// contents are read before the age is checked, so that a lock taken
+		// in between looks new rather than passing for the old one.
+		held, readErr := os.ReadFile(path)
+		if info, err := os.Stat(path); readErr == nil && err == nil && time.Since(info.ModTime()) > staleLockAge {
+			removeLock(path, held)
+			continue
+		}
+
+		if time.Now().After(deadline) {
+			return nil, fmt.Errorf("timed out waiting for lock %s", path)
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+}
+
+// createLock creates the lock file holding token, failing with an error
+// matching os.ErrExist if it is already there.
+func createLock(path string, token []byte) error {
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0o600)
+	if err != nil {
+		return err
+	}
+	_, err = file.Write(token)
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		os.Remove(path)
+	}
+	return err
+}
+
+// removeLock removes the lock file if it still holds token. Another waiter
+// may have broken an abandoned lock and taken a new one since token was
+// read, and that lock must be left alone.
+func removeLock(path string, token []byte) {
+	if current, err := os.ReadFile(path); err == nil && bytes.Equal(current, token) {
+		os.Remove(path)
+	}
+}
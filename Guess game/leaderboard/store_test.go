@@ -0,0 +1,76 @@
+package leaderboard
+
+import (
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestAddConcurrently(t *testing.T) {
+	store := Open(filepath.Join(t.TempDir(), "results.json"))
+
+	const writers = 20
+	var wg sync.WaitGroup
+	for i := 0; i < writers; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			if err := store.Add(Result{Player: "p", Attempts: i}); err != nil {
+				t.Error(err)
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	results, err := store.Results()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(results) != writers {
+		t.Errorf("%d results saved, want %d", len(results), writers)
+	}
+}
+
+func TestAddBreaksAbandonedLock(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "results.json")
+	lock := path + ".lock"
+	if err := os.WriteFile(lock, []byte("99999 dead\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	old := time.Now().Add(-2 * staleLockAge)
+	if err := os.Chtimes(lock, old, old); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := Open(path).Add(Result{Player: "p"}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(lock); !os.IsNotExist(err) {
+		t.Errorf("lock file left behind after Add: %v", err)
+	}
+}
+
+func TestRemoveLockLeavesOthersAlone(t *testing.T) {
+	lock := filepath.Join(t.TempDir(), "results.json.lock")
+
+	// A waiter read the abandoned lock, but another waiter broke it and
+	// took a new one before the first got round to removing it.
+	if err := createLock(lock, []byte("2 new\n")); err != nil {
+		t.Fatal(err)
+	}
+	removeLock(lock, []byte("1 abandoned\n"))
+	if _, err := os.Stat(lock); err != nil {
+		t.Fatalf("removeLock removed a lock it did not see: %v", err)
+	}
+
+	if err := createLock(lock, []byte("3 other\n")); !os.IsExist(err) {
+		t.Errorf("createLock over a held lock: error = %v, want it to exist", err)
+	}
+
+	removeLock(lock, []byte("2 new\n"))
+	if _, err := os.Stat(lock); !os.IsNotExist(err) {
+		t.Errorf("removeLock left its own lock behind: %v", err)
+	}
+}
@@ -0,0 +1,292 @@
+// Package multiplayer runs the guessing game as a party game over TCP. All
+// connected players race to find the same secret; the first one to get it
+// wins the round and a new round starts straight away. If everyone runs out
+// of attempts first, nobody wins and the next round starts.
+package multiplayer
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"math/rand"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/TheInvincibleRalph/Guessing-game.git/game"
+)
+
+const (
+	// DefaultReadTimeout disconnects players that stay silent this long.
+	DefaultReadTimeout = 5 * time.Minute
+
+	// writeTimeout bounds how long a slow client can hold up its writer.
+	writeTimeout = 10 * time.Second
+
+	// outboxSize is how many messages may queue up for a client before it
+	// is considered too slow and disconnected.
+	outboxSize = 64
+)
+
+// Server holds the shared round and the connected players.
+type Server struct {
+	// ReadTimeout is how long a player may go without sending a line.
+	ReadTimeout time.Duration
+
+	cfg game.Config
+	rng *rand.Rand
+
+	mu      sync.Mutex
+	round   int
+	secret  int
+	players map[*player]bool
+}
+
+// player is one connected client. Each player has a game of their own for
+// the current round so attempts are counted separately, but every game in a
+// round shares the same secret.
+type player struct {
+	name   string
+	conn   net.Conn
+	outbox chan string
+	game   *game.Game
+}
+
+// NewServer creates a server playing with cfg. Secrets are drawn from rng.
+func NewServer(cfg game.Config, rng *rand.Rand) (*Server, error) {
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+	if cfg.IsCode() {
+		return nil, fmt.Errorf("multiplayer only supports %s games", game.HigherLower)
+	}
+
+	s := &Server{
+		ReadTimeout: DefaultReadTimeout,
+		cfg:         cfg,
+		rng:         rng,
+		players:     map[*player]bool{},
+	}
+	s.newRound()
+	return s, nil
+}
+
+// Serve accepts connections on l until it is closed, handling each one in
+// its own goroutine.
+func (s *Server) Serve(l net.Listener) error {
+	for {
+		conn, err := l.Accept()
+		if errors.Is(err, net.ErrClosed) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		go s.Handle(conn)
+	}
+}
+
+// Handle plays with the client on conn until it quits, goes quiet for longer
+// than ReadTimeout or disconnects. It closes conn before returning.
+func (s *Server) Handle(conn net.Conn) {
+	defer conn.Close()
+
+	p := &player{conn: conn, outbox: make(chan string, outboxSize)}
+	done := make(chan struct{})
+	go p.writeLoop(done)
+	defer func() {
+		close(p.outbox)
+		<-done
+	}()
+
+	lines := bufio.NewScanner(conn)
+	p.send("Welcome to the guessing game! What is your name?")
+	name, ok := s.readLine(p, lines)
+	if !ok {
+		return
+	}
+	if name == "" {
+		name = conn.RemoteAddr().String()
+	}
+	p.name = name
+
+	s.join(p)
+	defer s.leave(p)
+
+	for {
+		line, ok := s.readLine(p, lines)
+		if !ok {
+			return
+		}
+
+		switch strings.ToLower(line) {
+		case "":
+			continue
+		case "quit":
+			p.send("Bye!")
+			return
+		}
+
+		guess, err := strconv.Atoi(line)
+		if err != nil {
+			p.send(fmt.Sprintf("%q is not a number, please try again", line))
+			continue
+		}
+		s.guess(p, guess)
+	}
+}
+
+// readLine waits up to ReadTimeout for the next line from the player.
+func (s *Server) readLine(p *player, lines *bufio.Scanner) (string, bool) {
+	p.conn.SetReadDeadline(time.Now().Add(s.ReadTimeout))
+	if !lines.Scan() {
+		var netErr net.Error
+		if errors.As(lines.Err(), &netErr) && netErr.Timeout() {
+			p.send("Disconnected for being idle for too long.")
+		}
+		return "", false
+	}
+	return strings.TrimSpace(lines.Text()), true
+}
+
+func (s *Server) join(p *player) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	p.game = s.newGame()
+	s.players[p] = true
+	s.broadcast(fmt.Sprintf("%s joined (%d playing)", p.name, len(s.players)))
+	p.send(s.roundIntro())
+}
+
+func (s *Server) leave(p *player) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.players, p)
+	s.broadcast(fmt.Sprintf("%s left (%d playing)", p.name, len(s.players)))
+
+	// The player who left may have been the last one with attempts left.
+	if len(s.players) > 0 && s.allOver() {
+		s.noWinner()
+	}
+}
+
+// guess plays one guess for p and tells everyone how it went.
+func (s *Server) guess(p *player, guess int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	outcome, err := p.game.Guess(guess)
+	switch {
+	case errors.Is(err, game.ErrGameOver):
+		p.send("You are out of attempts, wait for the next round.")
+		return
+	case errors.Is(err, game.ErrOutOfRange):
+		p.send(fmt.Sprintf("Your guess must be between %d and %d", s.cfg.Min, s.cfg.Max))
+		return
+	}
+
+	if outcome == game.Correct {
+		s.broadcast(fmt.Sprintf("*** %s wins round %d! The secret was %d, found in %d attempts ***", p.name, s.round, s.secret, p.game.Attempts()))
+		s.nextRound()
+		return
+	}
+
+	s.broadcast(fmt.Sprintf("%s guessed %d: %s", p.name, guess, outcome))
+	switch {
+	case !p.game.Over():
+	case s.allOver():
+		s.noWinner()
+	default:
+		p.send("You are out of attempts, wait for the next round.")
+	}
+}
+
+// allOver reports whether every player has run out of attempts. The caller
+// must hold s.mu.
+func (s *Server) allOver() bool {
+	for p := range s.players {
+		if !p.game.Over() {
+			return false
+		}
+	}
+	return true
+}
+
+// noWinner ends a round nobody won. The caller must hold s.mu.
+func (s *Server) noWinner() {
+	s.broadcast(fmt.Sprintf("*** Nobody wins round %d. The secret was %d ***", s.round, s.secret))
+	s.nextRound()
+}
+
+// nextRound starts a new round and gives every player a fresh game for it.
+// The caller must hold s.mu.
+func (s *Server) nextRound() {
+	s.newRound()
+	for p := range s.players {
+		p.game = s.newGame()
+	}
+	s.broadcast(s.roundIntro())
+}
+
+// newRound picks a new secret. The caller must hold s.mu.
+func (s *Server) newRound() {
+	s.round++
+	s.secret = s.cfg.RandomSecret(s.rng)
+}
+
+// newGame creates a game for the current round. The caller must hold s.mu.
+func (s *Server) newGame() *game.Game {
+	g, err := game.NewWithSecret(s.cfg, s.secret)
+	if err != nil {
+		// The config was validated and the secret drawn from its range.
+		panic(err)
+	}
+	return g
+}
+
+func (s *Server) roundIntro() string {
+	intro := fmt.Sprintf("Round %d: guess the secret number between %d and %d", s.round, s.cfg.Min, s.cfg.Max)
+	if s.cfg.MaxAttempts > 0 {
+		intro += fmt.Sprintf(", %d attempts each", s.cfg.MaxAttempts)
+	}
+	return intro
+}
+
+// broadcast sends msg to every player. The caller must hold s.mu.
+func (s *Server) broadcast(msg string) {
+	for p := range s.players {
+		p.send(msg)
+	}
+}
+
+// send queues msg for the player without blocking. A player whose queue is
+// full is too slow to keep up and gets disconnected.
+func (p *player) send(msg string) {
+	select {
+	case p.outbox <- msg:
+	default:
+		p.conn.Close()
+	}
+}
+
+// writeLoop writes queued messages until the outbox is closed.
+func (p *player) writeLoop(done chan<- struct{}) {
+	defer close(done)
+	for msg := range p.outbox {
+		p.conn.SetWriteDeadline(time.Now().Add(writeTimeout))
+		if _, err := io.WriteString(p.conn, msg+"\n"); err != nil {
+			log.Printf("Failed to write to %s: %s", p.conn.RemoteAddr(), err)
+			p.conn.Close()
+			// Keep draining so senders never block on a dead connection.
+			for range p.outbox {
+			}
+			return
+		}
+	}
+}
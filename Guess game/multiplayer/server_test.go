@@ -0,0 +1,216 @@
+package multiplayer
+
+import (
+	"bufio"
+	"fmt"
+	"math"
+	"math/rand"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/TheInvincibleRalph/Guessing-game.git/game"
+)
+
+// client is the test's end of a connection to the server.
+type client struct {
+	t     *testing.T
+	conn  net.Conn
+	lines *bufio.Reader
+}
+
+func newServer(t *testing.T, cfg game.Config) *Server {
+	t.Helper()
+	s, err := NewServer(cfg, rand.New(rand.NewSource(1)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return s
+}
+
+// connect joins s over a net.Pipe as name and waits for the round intro.
+func connect(t *testing.T, s *Server, name string) *client {
+	t.Helper()
+	server, conn := net.Pipe()
+	go s.Handle(server)
+
+	c := &client{t: t, conn: conn, lines: bufio.NewReader(conn)}
+	t.Cleanup(func() { conn.Close() })
+	c.expect("What is your name?")
+	c.send(name)
+	c.expect("Round ")
+	return c
+}
+
+func (c *client) send(line string) {
+	c.t.Helper()
+	c.conn.SetWriteDeadline(time.Now().Add(time.Second))
+	if _, err := fmt.Fprintln(c.conn, line); err != nil {
+		c.t.Fatalf("sending %q: %v", line, err)
+	}
+}
+
+// expect reads lines until one contains want, failing if none does within
+// a second.
+func (c *client) expect(want string) string {
+	c.t.Helper()
+	c.conn.SetReadDeadline(time.Now().Add(time.Second))
+	var seen []string
+	for {
+		line, err := c.lines.ReadString('\n')
+		if err != nil {
+			c.t.Fatalf("waiting for %q: %v (got %q)", want, err, seen)
+		}
+		line = strings.TrimSpace(line)
+		if strings.Contains(line, want) {
+			return line
+		}
+		seen = append(seen, line)
+	}
+}
+
+// secret returns the secret of the current round and a guess that misses it.
+func secret(s *Server) (int, int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	wrong := s.cfg.Min
+	if s.secret == wrong {
+		wrong = s.cfg.Max
+	}
+	return s.secret, wrong
+}
+
+func TestWideRange(t *testing.T) {
+	if _, err := NewServer(game.Config{Min: 0, Max: math.MaxInt}, rand.New(rand.NewSource(1))); err == nil {
+		t.Error("NewServer accepted a range of more than math.MaxInt numbers")
+	}
+
+	s := newServer(t, game.Config{Min: 1, Max: math.MaxInt})
+	for i := 0; i < 100; i++ {
+		s.newRound()
+		if s.secret < 1 {
+			t.Fatalf("round %d drew %d", s.round, s.secret)
+		}
+	}
+}
+
+func TestWinnerStartsNewRound(t *testing.T) {
+	s := newServer(t, game.Config{Min: 1, Max: 100, MaxAttempts: 10})
+	alice := connect(t, s, "alice")
+	bob := connect(t, s, "bob")
+	alice.expect("bob joined (2 playing)")
+
+	answer, wrong := secret(s)
+	bob.send(fmt.Sprint(wrong))
+	alice.expect(fmt.Sprintf("bob guessed %d: too", wrong))
+
+	alice.send(fmt.Sprint(answer))
+	for _, c := range []*client{alice, bob} {
+		c.expect(fmt.Sprintf("*** alice wins round 1! The secret was %d, found in 1 attempts ***", answer))
+		c.expect("Round 2:")
+	}
+}
+
+func TestEveryoneOutOfAttemptsStartsNewRound(t *testing.T) {
+	s := newServer(t, game.Config{Min: 1, Max: 100, MaxAttempts: 1})
+	alice := connect(t, s, "alice")
+	bob := connect(t, s, "bob")
+	alice.expect("bob joined")
+
+	answer, wrong := secret(s)
+	alice.send(fmt.Sprint(wrong))
+	alice.expect("You are out of attempts, wait for the next round.")
+	alice.send(fmt.Sprint(answer))
+	alice.expect("You are out of attempts, wait for the next round.")
+
+	bob.send(fmt.Sprint(wrong))
+	for _, c := range []*client{alice, bob} {
+		c.expect(fmt.Sprintf("*** Nobody wins round 1. The secret was %d ***", answer))
+		c.expect("Round 2:")
+	}
+
+	// Everyone has attempts again in the new round.
+	answer, _ = secret(s)
+	bob.send(fmt.Sprint(answer))
+	alice.expect("*** bob wins round 2!")
+}
+
+func TestLeavingEndsRoundForPlayersOutOfAttempts(t *testing.T) {
+	s := newServer(t, game.Config{Min: 1, Max: 100, MaxAttempts: 1})
+	alice := connect(t, s, "alice")
+	bob := connect(t, s, "bob")
+	alice.expect("bob joined")
+
+	_, wrong := secret(s)
+	alice.send(fmt.Sprint(wrong))
+	alice.expect("You are out of attempts")
+
+	bob.send("quit")
+	bob.expect("Bye!")
+	alice.expect("bob left (1 playing)")
+	alice.expect("*** Nobody wins round 1.")
+	alice.expect("Round 2:")
+}
+
+func TestBadInput(t *testing.T) {
+	s := newServer(t, game.Config{Min: 1, Max: 100, MaxAttempts: 1})
+	alice := connect(t, s, "alice")
+
+	alice.send("forty")
+	alice.expect(`"forty" is not a number, please try again`)
+	alice.send("1000")
+	alice.expect("Your guess must be between 1 and 100")
+
+	// Neither used up the only attempt.
+	answer, _ := secret(s)
+	alice.send(fmt.Sprint(answer))
+	alice.expect("*** alice wins round 1!")
+}
+
+func TestIdlePlayerIsDisconnected(t *testing.T) {
+	s := newServer(t, game.Config{Min: 1, Max: 100})
+	s.ReadTimeout = 50 * time.Millisecond
+
+	alice := connect(t, s, "alice")
+	alice.expect("Disconnected for being idle for too long.")
+	alice.conn.SetReadDeadline(time.Now().Add(time.Second))
+	if _, err := alice.lines.ReadString('\n'); err == nil {
+		t.Error("connection still open after the idle message")
+	}
+}
+
+func TestServeOverLoopback(t *testing.T) {
+	s := newServer(t, game.Config{Min: 1, Max: 100})
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	served := make(chan error, 1)
+	go func() { served <- s.Serve(l) }()
+
+	conns := make([]*client, 3)
+	for i := range conns {
+		conn, err := net.Dial("tcp", l.Addr().String())
+		if err != nil {
+			t.Fatal(err)
+		}
+		c := &client{t: t, conn: conn, lines: bufio.NewReader(conn)}
+		t.Cleanup(func() { conn.Close() })
+		c.expect("What is your name?")
+		c.send(fmt.Sprint("player", i))
+		c.expect("Round 1:")
+		conns[i] = c
+	}
+
+	answer, _ := secret(s)
+	conns[2].send(fmt.Sprint(answer))
+	for _, c := range conns {
+		c.expect("*** player2 wins round 1!")
+	}
+
+	l.Close()
+	if err := <-served; err != nil {
+		t.Errorf("Serve returned %v after the listener closed", err)
+	}
+}
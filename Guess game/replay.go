@@ -0,0 +1,42 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"os"
+
+	"github.com/TheInvincibleRalph/Guessing-game.git/game"
+)
+
+// Create a function to re-run a recorded game and verify every response
+func replay(args []string) {
+	if len(args) != 1 {
+		fmt.Fprintln(os.Stderr, "Usage: guess replay transcript.json")
+		os.Exit(2)
+	}
+
+	t, err := game.LoadTranscript(args[0])
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	for i, m := range t.Moves {
+		switch m.Action {
+		case "guess":
+			if t.Config.IsCode() {
+				fmt.Printf("%3d. guess %s: %s\n", i+1, m.Code, m.Response)
+			} else {
+				fmt.Printf("%3d. guess %d: %s\n", i+1, m.Guess, m.Response)
+			}
+		case "hint":
+			fmt.Printf("%3d. hint: %s\n", i+1, m.Response)
+		default:
+			fmt.Printf("%3d. %s\n", i+1, m.Action)
+		}
+	}
+
+	if _, err := game.Replay(t); err != nil {
+		log.Fatal("Replay does not match the transcript: ", err)
+	}
+	fmt.Printf("Transcript verified: %d moves replayed with the same responses\n", len(t.Moves))
+}
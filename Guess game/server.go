@@ -0,0 +1,34 @@
+package main
+
+import (
+	"flag"
+	"log"
+	"math/rand"
+	"net"
+	"time"
+
+	"github.com/TheInvincibleRalph/Guessing-game.git/multiplayer"
+)
+
+// Create a function to host a multiplayer game that players join with telnet or nc
+func server(args []string) {
+	flags := flag.NewFlagSet("server", flag.ExitOnError)
+	config := configFlags(flags)
+	addr := flags.String("addr", ":4000", "address to listen on")
+	idle := flags.Duration("idle", multiplayer.DefaultReadTimeout, "disconnect players that send nothing for this long")
+	flags.Parse(args)
+	cfg := config()
+
+	srv, err := multiplayer.NewServer(cfg, rand.New(rand.NewSource(time.Now().UnixNano())))
+	if err != nil {
+		log.Fatal(err)
+	}
+	srv.ReadTimeout = *idle
+
+	listener, err := net.Listen("tcp", *addr)
+	if err != nil {
+		log.Fatal(err)
+	}
+	log.Printf("Guessing game server listening on %s", listener.Addr())
+	log.Fatal(srv.Serve(listener))
+}
@@ -0,0 +1,53 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"math/rand"
+
+	"github.com/TheInvincibleRalph/Guessing-game.git/bot"
+	"github.com/TheInvincibleRalph/Guessing-game.git/game"
+)
+
+// Create a function to let a bot play many games and report its attempts
+func simulate(args []string) {
+	flags := flag.NewFlagSet("simulate", flag.ExitOnError)
+	config := configFlags(flags)
+	name := flags.String("bot", "", fmt.Sprintf("bot to play with: %v for %s (binary by default), %v for %s", bot.Names[game.HigherLower], game.HigherLower, bot.Names[game.BullsAndCows], game.BullsAndCows))
+	games := flags.Int("games", 1000, "number of games to play")
+	seed := seedFlag(flags)
+	flags.Parse(args)
+	cfg := config()
+
+	if *name == "" {
+		*name = bot.Names[cfg.Mode][0]
+	}
+
+	rng := rand.New(rand.NewSource(seed()))
+	player, err := bot.New(*name, cfg, rng)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	report, err := bot.Simulate(player, cfg, *games, rng)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	fmt.Printf("Bot:           %s\n", *name)
+	if cfg.IsCode() {
+		fmt.Printf("Code:          %d digits, unique: %t\n", cfg.Digits, cfg.Unique)
+	} else {
+		fmt.Printf("Range:         %d to %d\n", cfg.Min, cfg.Max)
+	}
+	fmt.Printf("Games played:  %d\n", report.Games)
+	fmt.Printf("Win rate:      %.1f%%\n", float64(report.Wins)/float64(max(report.Games, 1))*100)
+	if report.Wins == 0 {
+		return
+	}
+	fmt.Printf("Attempts:      min %d, max %d, mean %.2f\n", report.MinAttempts, report.MaxAttempts, report.MeanAttempts)
+	fmt.Println()
+	fmt.Println("Attempts per won game:")
+	printHistogram(report.Histogram)
+}
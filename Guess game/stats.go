@@ -0,0 +1,91 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/TheInvincibleRalph/Guessing-game.git/game"
+	"github.com/TheInvincibleRalph/Guessing-game.git/leaderboard"
+)
+
+// Create a function to print the statistics of saved games
+func stats(args []string) {
+	flags := flag.NewFlagSet("stats", flag.ExitOnError)
+	name := flags.String("name", "", "only include games by this player")
+	file := flags.String("file", leaderboard.DefaultPath(), "file the results are read from")
+	flags.Parse(args)
+
+	results, err := leaderboard.Open(*file).Results()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	s := leaderboard.Summarise(results, *name)
+	if s.Games == 0 {
+		fmt.Println("No games played yet")
+		return
+	}
+
+	fmt.Printf("Games played:     %d\n", s.Games)
+	fmt.Printf("Win rate:         %.1f%%\n", s.WinRate()*100)
+	fmt.Printf("Best score:       %d\n", s.BestScore)
+	fmt.Printf("Average attempts: %.2f\n", s.AverageAttempts)
+	fmt.Println()
+	fmt.Println("Attempts per game:")
+	printHistogram(s.Histogram)
+}
+
+// Create a function to print the best scores
+func showLeaderboard(args []string) {
+	flags := flag.NewFlagSet("leaderboard", flag.ExitOnError)
+	top := flags.Int("top", 10, "number of entries to show")
+	mode := flags.String("mode", "", "only include games of this mode")
+	difficulty := flags.String("difficulty", "", "only include games played on this difficulty")
+	file := flags.String("file", leaderboard.DefaultPath(), "file the results are read from")
+	flags.Parse(args)
+
+	results, err := leaderboard.Open(*file).Results()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	best := leaderboard.Top(results, *top, game.Mode(*mode), game.Difficulty(*difficulty))
+	if len(best) == 0 {
+		fmt.Println("No winning games yet")
+		return
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "RANK\tPLAYER\tSCORE\tMODE\tDIFFICULTY\tATTEMPTS\tDATE")
+	for i, r := range best {
+		fmt.Fprintf(w, "%d\t%s\t%d\t%s\t%s\t%d\t%s\n", i+1, r.Player, r.Score, r.GameMode(), r.Difficulty, r.Attempts, r.PlayedAt.Local().Format("2006-01-02 15:04"))
+	}
+	w.Flush()
+}
+
+// Create a function to draw a bar for each number of attempts
+func printHistogram(histogram map[int]int) {
+	var attempts []int
+	most := 0
+	for a, n := range histogram {
+		attempts = append(attempts, a)
+		most = max(most, n)
+	}
+	sort.Ints(attempts)
+
+	//Scale the bars down so the longest is at most 40 characters
+	const width = 40
+	for _, a := range attempts {
+		n := histogram[a]
+		bar := n
+		if most > width {
+			bar = max(1, n*width/most)
+		}
+		fmt.Printf("%4d | %s %d\n", a, strings.Repeat("#", bar), n)
+	}
+}
@@ -0,0 +1,71 @@
+package shell
+
+import (
+	"bufio"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// history keeps every entry typed into the shell and appends each one to a
+// file so it survives between sessions. Multi-line statements are stored on
+// a single line.
+type history struct {
+	path    string
+	entries []string
+}
+
+// historyPath returns GDA_HISTORY if it is set, otherwise ~/.gda_history.
+func historyPath() string {
+	if path, ok := os.LookupEnv("GDA_HISTORY"); ok {
+		return path
+	}
+
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return ".gda_history"
+	}
+	return filepath.Join(home, ".gda_history")
+}
+
+// loadHistory reads any existing entries from path. A missing file is not an
+// error; it is created on the first append.
+func loadHistory(path string) (*history, error) {
+	h := &history{path: path}
+
+	file, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return h, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		if line := scanner.Text(); line != "" {
+			h.entries = append(h.entries, line)
+		}
+	}
+	return h, scanner.Err()
+}
+
+// append records entry in memory and on disk.
+func (h *history) append(entry string) error {
+	entry = strings.TrimSpace(strings.ReplaceAll(entry, "\n", " "))
+	if entry == "" {
+		return nil
+	}
+	h.entries = append(h.entries, entry)
+
+	file, err := os.OpenFile(h.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o600)
+	if err != nil {
+		return err
+	}
+	if _, err := file.WriteString(entry + "\n"); err != nil {
+		file.Close()
+		return err
+	}
+	return file.Close()
+}
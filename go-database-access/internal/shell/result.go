@@ -0,0 +1,129 @@
+package shell
+
+import (
+	"database/sql"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"unicode/utf8"
+)
+
+// nullDisplay is what a NULL column looks like in a rendered table. It is
+// kept distinct from the empty string so the two can be told apart.
+const nullDisplay = "(null)"
+
+// result holds a fully read result set so it can be rendered and exported
+// after the underlying rows have been closed.
+type result struct {
+	columns []string
+	rows    [][]sql.NullString
+}
+
+// readResult drains rows into a result. Every column is scanned into a
+// sql.NullString, which lets the driver convert any value to text while
+// still telling us when the database returned NULL.
+func readResult(rows *sql.Rows) (*result, error) {
+	columns, err := rows.Columns()
+	if err != nil {
+		return nil, err
+	}
+
+	res := &result{columns: columns}
+	for rows.Next() {
+		row := make([]sql.NullString, len(columns))
+		dest := make([]any, len(columns))
+		for i := range row {
+			dest[i] = &row[i]
+		}
+		if err := rows.Scan(dest...); err != nil {
+			return nil, err
+		}
+		res.rows = append(res.rows, row)
+	}
+
+	return res, rows.Err()
+}
+
+// render writes the result as an aligned text table followed by a row count.
+func (r *result) render(w io.Writer) {
+	widths := make([]int, len(r.columns))
+	for i, c := range r.columns {
+		widths[i] = utf8.RuneCountInString(c)
+	}
+	for _, row := range r.rows {
+		for i, v := range row {
+			if n := utf8.RuneCountInString(display(v)); n > widths[i] {
+				widths[i] = n
+			}
+		}
+	}
+
+	cells := make([]string, len(r.columns))
+	for i, c := range r.columns {
+		cells[i] = pad(c, widths[i])
+	}
+	fmt.Fprintf(w, " %s \n", strings.Join(cells, " | "))
+
+	rules := make([]string, len(r.columns))
+	for i, width := range widths {
+		rules[i] = strings.Repeat("-", width)
+	}
+	fmt.Fprintf(w, "-%s-\n", strings.Join(rules, "-+-"))
+
+	for _, row := range r.rows {
+		for i, v := range row {
+			cells[i] = pad(display(v), widths[i])
+		}
+		fmt.Fprintf(w, " %s \n", strings.Join(cells, " | "))
+	}
+
+	if len(r.rows) == 1 {
+		fmt.Fprintln(w, "(1 row)")
+	} else {
+		fmt.Fprintf(w, "(%d rows)\n", len(r.rows))
+	}
+}
+
+// exportCSV writes the result to path with a header row. NULLs become empty
+// fields since CSV has no way of representing them.
+func (r *result) exportCSV(path string) error {
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	writer := csv.NewWriter(file)
+	if err := writer.Write(r.columns); err != nil {
+		return err
+	}
+
+	record := make([]string, len(r.columns))
+	for _, row := range r.rows {
+		for i, v := range row {
+			record[i] = v.String
+		}
+		if err := writer.Write(record); err != nil {
+			return err
+		}
+	}
+
+	writer.Flush()
+	if err := writer.Error(); err != nil {
+		return err
+	}
+	return file.Close()
+}
+
+func display(v sql.NullString) string {
+	if !v.Valid {
+		return nullDisplay
+	}
+	return v.String
+}
+
+func pad(s string, width int) string {
+	return s + strings.Repeat(" ", width-utf8.RuneCountInString(s))
+}
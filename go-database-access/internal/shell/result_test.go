@@ -0,0 +1,102 @@
+package shell
+
+import (
+	"database/sql"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func text(s string) sql.NullString { return sql.NullString{String: s, Valid: true} }
+
+var null sql.NullString
+
+func TestRender(t *testing.T) {
+	tests := []struct {
+		name string
+		res  *result
+		want string
+	}{
+		{
+			"aligned columns",
+			&result{
+				columns: []string{"id", "title"},
+				rows: [][]sql.NullString{
+					{text("1"), text("Blue Train")},
+					{text("10"), text("Giant Steps")},
+				},
+			},
+			"" +
+				" id | title       \n" +
+				"----+-------------\n" +
+				" 1  | Blue Train  \n" +
+				" 10 | Giant Steps \n" +
+				"(2 rows)\n",
+		},
+		{
+			"nulls and empty strings",
+			&result{
+				columns: []string{"a", "b"},
+				rows:    [][]sql.NullString{{null, text("")}},
+			},
+			"" +
+				" a      | b \n" +
+				"--------+---\n" +
+				" (null) |   \n" +
+				"(1 row)\n",
+		},
+		{
+			"multibyte text",
+			&result{
+				columns: []string{"artist"},
+				rows:    [][]sql.NullString{{text("Sigur Rós")}, {text("Björk")}},
+			},
+			"" +
+				" artist    \n" +
+				"-----------\n" +
+				" Sigur Rós \n" +
+				" Björk     \n" +
+				"(2 rows)\n",
+		},
+		{
+			"no rows",
+			&result{columns: []string{"id"}},
+			" id \n----\n(0 rows)\n",
+		},
+	}
+	for _, tt := range tests {
+		var out strings.Builder
+		tt.res.render(&out)
+		if got := out.String(); got != tt.want {
+			t.Errorf("%s: render wrote\n%s\nwant\n%s", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestExportCSV(t *testing.T) {
+	res := &result{
+		columns: []string{"id", "title"},
+		rows: [][]sql.NullString{
+			{text("1"), text(`Kind of "Blue", 1959`)},
+			{text("2"), null},
+		},
+	}
+	path := filepath.Join(t.TempDir(), "out.csv")
+	if err := res.exportCSV(path); err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "id,title\n1,\"Kind of \"\"Blue\"\", 1959\"\n2,\n"
+	if string(data) != want {
+		t.Errorf("exportCSV wrote %q, want %q", data, want)
+	}
+
+	if err := res.exportCSV(filepath.Join(t.TempDir(), "missing", "out.csv")); err == nil {
+		t.Error("exportCSV into a missing directory succeeded")
+	}
+}
@@ -0,0 +1,373 @@
+package shell
+
+import (
+	"bufio"
+	"context"
+	"database/sql"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"os/signal"
+	"strings"
+	"time"
+	"unicode"
+
+	_ "github.com/jackc/pgx/v5/stdlib"
+	"woojiahao.com/gda/internal/utility"
+)
+
+const helpText = `Statements end with a semicolon and may span several lines.
+
+Meta-commands:
+  \dt                   list tables
+  \d <table>            describe the columns of a table
+  \export csv <file>    write the last result set to a CSV file
+  \s                    show the command history
+  \?                    show this help
+  \q                    quit
+`
+
+const listTablesQuery = `
+SELECT table_schema AS schema, table_name AS name, table_type AS type
+FROM information_schema.tables
+WHERE table_schema NOT IN ('pg_catalog', 'information_schema')
+ORDER BY table_schema, table_name;
+`
+
+const describeTableQuery = `
+SELECT column_name AS column, data_type AS type, is_nullable AS nullable, column_default AS default
+FROM information_schema.columns
+WHERE table_name = $1
+ORDER BY ordinal_position;
+`
+
+// shell is an interactive SQL prompt on top of gda's database connection.
+type shell struct {
+	db      *sql.DB
+	in      *bufio.Scanner
+	out     io.Writer
+	history *history
+
+	// last is the most recent result set, kept around for \export.
+	last *result
+
+	// interrupt returns the context a statement runs under. It is cancelled
+	// by Ctrl-C, which stops the statement but leaves the shell running.
+	interrupt func() (context.Context, context.CancelFunc)
+}
+
+// Run connects with the CONN_STR connection string and starts a shell on
+// stdin and stdout.
+func Run() {
+	db, err := sql.Open("pgx", utility.ConnectionString())
+	if err != nil {
+		log.Fatalf("Failed to connect to database because %s", err)
+	}
+	defer db.Close()
+
+	if err = db.Ping(); err != nil {
+		log.Fatalf("Database cannot be reached because %s", err)
+	}
+
+	h, err := loadHistory(historyPath())
+	if err != nil {
+		log.Fatalf("Unable to read shell history because %s", err)
+	}
+
+	s := newShell(db, os.Stdin, os.Stdout, h)
+	s.loop()
+}
+
+// newShell creates a shell that reads from in and writes to out.
+func newShell(db *sql.DB, in io.Reader, out io.Writer, h *history) *shell {
+	return &shell{
+		db:      db,
+		in:      bufio.NewScanner(in),
+		out:     out,
+		history: h,
+		interrupt: func() (context.Context, context.CancelFunc) {
+			return signal.NotifyContext(context.Background(), os.Interrupt)
+		},
+	}
+}
+
+// loop reads statements and meta-commands until \q or end of input.
+func (s *shell) loop() {
+	fmt.Fprintln(s.out, `gda shell. Type \? for help.`)
+
+	var buffer strings.Builder
+	for {
+		if buffer.Len() == 0 {
+			fmt.Fprint(s.out, "gda=> ")
+		} else {
+			fmt.Fprint(s.out, "gda-> ")
+		}
+
+		if !s.in.Scan() {
+			fmt.Fprintln(s.out)
+			return
+		}
+		line := strings.TrimSpace(s.in.Text())
+
+		// Meta-commands are only recognised at the start of a statement.
+		if buffer.Len() == 0 && strings.HasPrefix(line, `\`) {
+			s.record(line)
+			if quit := s.meta(line); quit {
+				return
+			}
+			continue
+		}
+
+		if line == "" {
+			continue
+		}
+		if buffer.Len() > 0 {
+			buffer.WriteString("\n")
+		}
+		buffer.WriteString(line)
+
+		if strings.HasSuffix(line, ";") {
+			statement := buffer.String()
+			buffer.Reset()
+			s.record(statement)
+			s.execute(statement)
+		}
+	}
+}
+
+func (s *shell) record(entry string) {
+	if err := s.history.append(entry); err != nil {
+		fmt.Fprintf(s.out, "Unable to save history: %s\n", err)
+	}
+}
+
+// meta runs a backslash command and reports whether the shell should exit.
+func (s *shell) meta(line string) bool {
+	fields := strings.Fields(line)
+	switch fields[0] {
+	case `\q`:
+		return true
+	case `\?`:
+		fmt.Fprint(s.out, helpText)
+	case `\s`:
+		for i, entry := range s.history.entries {
+			fmt.Fprintf(s.out, "%5d  %s\n", i+1, entry)
+		}
+	case `\dt`:
+		s.query(listTablesQuery)
+	case `\d`:
+		if len(fields) != 2 {
+			fmt.Fprintln(s.out, `Usage: \d <table>`)
+			break
+		}
+		s.query(describeTableQuery, strings.Trim(fields[1], `"`))
+	case `\export`:
+		if len(fields) != 3 || fields[1] != "csv" {
+			fmt.Fprintln(s.out, `Usage: \export csv <file>`)
+			break
+		}
+		s.export(fields[2])
+	default:
+		fmt.Fprintf(s.out, "Unknown command %s. Type \\? for help.\n", fields[0])
+	}
+	return false
+}
+
+// execute runs a statement typed at the prompt. Statements that produce rows
+// are rendered as a table, anything else reports the number of rows affected.
+func (s *shell) execute(statement string) {
+	if returnsRows(statement) {
+		s.query(statement)
+		return
+	}
+
+	ctx, stop := s.interrupt()
+	defer stop()
+
+	start := time.Now()
+	res, err := s.db.ExecContext(ctx, statement)
+	elapsed := time.Since(start)
+	if err != nil {
+		fmt.Fprintf(s.out, "ERROR: %s\n", err)
+		return
+	}
+
+	if n, err := res.RowsAffected(); err == nil {
+		fmt.Fprintf(s.out, "OK, %d rows affected\n", n)
+	} else {
+		fmt.Fprintln(s.out, "OK")
+	}
+	s.timing(elapsed)
+}
+
+func (s *shell) query(statement string, args ...any) {
+	ctx, stop := s.interrupt()
+	defer stop()
+
+	start := time.Now()
+	rows, err := s.db.QueryContext(ctx, statement, args...)
+	if err != nil {
+		fmt.Fprintf(s.out, "ERROR: %s\n", err)
+		return
+	}
+	defer rows.Close()
+
+	res, err := readResult(rows)
+	elapsed := time.Since(start)
+	if err != nil {
+		fmt.Fprintf(s.out, "ERROR: %s\n", err)
+		return
+	}
+
+	s.last = res
+	res.render(s.out)
+	s.timing(elapsed)
+}
+
+func (s *shell) export(path string) {
+	if s.last == nil {
+		fmt.Fprintln(s.out, "Nothing to export, run a query first")
+		return
+	}
+	if err := s.last.exportCSV(path); err != nil {
+		fmt.Fprintf(s.out, "Unable to export to %s: %s\n", path, err)
+		return
+	}
+	fmt.Fprintf(s.out, "Exported %d rows to %s\n", len(s.last.rows), path)
+}
+
+func (s *shell) timing(elapsed time.Duration) {
+	fmt.Fprintf(s.out, "Time: %.3f ms\n", float64(elapsed.Microseconds())/1000)
+}
+
+// returnsRows guesses whether a statement produces a result set from its
+// leading keyword, or from a RETURNING clause on a data-modifying statement.
+// Words inside string literals, quoted identifiers and comments do not
+// count.
+func returnsRows(statement string) bool {
+	words := keywords(statement)
+	if len(words) == 0 {
+		return false
+	}
+
+	switch words[0] {
+	case "SELECT", "WITH", "SHOW", "VALUES", "TABLE", "EXPLAIN":
+		return true
+	}
+	for _, word := range words[1:] {
+		if word == "RETURNING" {
+			return true
+		}
+	}
+	return false
+}
+
+// keywords returns the bare words of a statement in upper case, skipping
+// string literals ('...', E'...' and $tag$...$tag$), quoted identifiers and
+// comments. Bare words are keywords and unquoted identifiers alike.
+func keywords(statement string) []string {
+	var words []string
+	r := []rune(statement)
+	for i := 0; i < len(r); {
+		switch {
+		case r[i] == '-' && i+1 < len(r) && r[i+1] == '-':
+			for i < len(r) && r[i] != '\n' {
+				i++
+			}
+		case r[i] == '/' && i+1 < len(r) && r[i+1] == '*':
+			i = skipBlockComment(r, i)
+		case r[i] == '\'':
+			i = skipQuoted(r, i, '\'', false)
+		case r[i] == '"':
+			i = skipQuoted(r, i, '"', false)
+		case r[i] == '$':
+			i = skipDollarQuoted(r, i)
+		case isWordStart(r[i]):
+			start := i
+			for i < len(r) && isWordPart(r[i]) {
+				i++
+			}
+			word := strings.ToUpper(string(r[start:i]))
+			// E'...' is a string that takes backslash escapes.
+			if word == "E" && i < len(r) && r[i] == '\'' {
+				i = skipQuoted(r, i, '\'', true)
+				continue
+			}
+			words = append(words, word)
+		default:
+			i++
+		}
+	}
+	return words
+}
+
+// skipQuoted returns the index just past the quoted text starting at r[i].
+// A doubled quote stands for the quote itself, and with backslashes set a
+// backslash escapes the character after it.
+func skipQuoted(r []rune, i int, quote rune, backslashes bool) int {
+	for i++; i < len(r); i++ {
+		switch {
+		case backslashes && r[i] == '\\':
+			i++
+		case r[i] == quote && i+1 < len(r) && r[i+1] == quote:
+			i++
+		case r[i] == quote:
+			return i + 1
+		}
+	}
+	return len(r)
+}
+
+// skipBlockComment returns the index just past the comment starting at
+// r[i]. Block comments nest in PostgreSQL.
+func skipBlockComment(r []rune, i int) int {
+	depth := 0
+	for i < len(r) {
+		switch {
+		case r[i] == '/' && i+1 < len(r) && r[i+1] == '*':
+			depth++
+			i += 2
+		case r[i] == '*' && i+1 < len(r) && r[i+1] == '/':
+			depth--
+			i += 2
+			if depth == 0 {
+				return i
+			}
+		default:
+			i++
+		}
+	}
+	return len(r)
+}
+
+// skipDollarQuoted returns the index just past the dollar-quoted string
+// starting at r[i], as in $$...$$ or $body$...$body$. A $ that does not
+// open one, as in the parameter $1, is skipped on its own.
+func skipDollarQuoted(r []rune, i int) int {
+	end := i + 1
+	for end < len(r) && r[end] != '$' {
+		if !isWordPart(r[end]) || end == i+1 && unicode.IsDigit(r[end]) {
+			return i + 1
+		}
+		end++
+	}
+	if end >= len(r) {
+		return i + 1
+	}
+
+	tag := string(r[i : end+1])
+	body := string(r[end+1:])
+	if closing := strings.Index(body, tag); closing >= 0 {
+		return end + 1 + len([]rune(body[:closing])) + len([]rune(tag))
+	}
+	return len(r)
+}
+
+func isWordStart(c rune) bool {
+	return c == '_' || unicode.IsLetter(c)
+}
+
+func isWordPart(c rune) bool {
+	return c == '_' || c == '$' || unicode.IsLetter(c) || unicode.IsDigit(c)
+}
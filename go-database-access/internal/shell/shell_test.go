@@ -0,0 +1,124 @@
+package shell
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestReturnsRows(t *testing.T) {
+	tests := []struct {
+		statement string
+		want      bool
+	}{
+		{"SELECT 1;", true},
+		{"  select * from album;", true},
+		{"WITH t AS (SELECT 1) SELECT * FROM t;", true},
+		{"show search_path;", true},
+		{"VALUES (1), (2);", true},
+		{"TABLE album;", true},
+		{"explain analyze select 1;", true},
+		{"-- the albums\nSELECT * FROM album;", true},
+		{"/* a /* nested */ comment */ SELECT 1;", true},
+		{"INSERT INTO album (title) VALUES ('x') RETURNING id;", true},
+		{"delete from album where id = $1 returning *;", true},
+
+		{"INSERT INTO album (title) VALUES ('x');", false},
+		{"INSERT INTO album (title) VALUES ('RETURNING');", false},
+		{"INSERT INTO album (title) VALUES ('it''s RETURNING');", false},
+		{`INSERT INTO album (title) VALUES (E'it\'s RETURNING');`, false},
+		{"INSERT INTO album (title) VALUES ($$ RETURNING $$);", false},
+		{"INSERT INTO album (title) VALUES ($q$ it's RETURNING $q$);", false},
+		{`UPDATE album SET "returning" = 1;`, false},
+		{"UPDATE album SET returning_id = 1;", false},
+		{"UPDATE album SET title = 'x' -- RETURNING id\n;", false},
+		{"UPDATE album SET title = 'x' /* RETURNING id */;", false},
+		{"CREATE TABLE album (id serial);", false},
+		{"", false},
+		{";", false},
+	}
+	for _, tt := range tests {
+		if got := returnsRows(tt.statement); got != tt.want {
+			t.Errorf("returnsRows(%q) = %v, want %v", tt.statement, got, tt.want)
+		}
+	}
+}
+
+func TestKeywords(t *testing.T) {
+	tests := []struct {
+		statement string
+		want      []string
+	}{
+		{"select a$b, $1 from t;", []string{"SELECT", "A$B", "FROM", "T"}},
+		{"select 'unterminated", []string{"SELECT"}},
+		{"select $$unterminated", []string{"SELECT"}},
+		{"select $body$ x $body$ as café", []string{"SELECT", "AS", "CAFÉ"}},
+	}
+	for _, tt := range tests {
+		if got := keywords(tt.statement); fmt.Sprint(got) != fmt.Sprint(tt.want) {
+			t.Errorf("keywords(%q) = %q, want %q", tt.statement, got, tt.want)
+		}
+	}
+}
+
+// run feeds input to a shell with no database behind it and returns what
+// it printed, without the banner and prompts.
+func run(t *testing.T, h *history, input string) string {
+	t.Helper()
+	var out strings.Builder
+	newShell(nil, strings.NewReader(input), &out, h).loop()
+
+	text := strings.TrimPrefix(out.String(), "gda shell. Type \\? for help.\n")
+	text = strings.ReplaceAll(text, "gda=> ", "")
+	return strings.ReplaceAll(text, "gda-> ", "")
+}
+
+func TestMeta(t *testing.T) {
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{`\?`, helpText},
+		{`\d`, "Usage: \\d <table>\n"},
+		{`\d one two`, "Usage: \\d <table>\n"},
+		{`\export`, "Usage: \\export csv <file>\n"},
+		{`\export json out.json`, "Usage: \\export csv <file>\n"},
+		{`\export csv out.csv`, "Nothing to export, run a query first\n"},
+		{`\x`, "Unknown command \\x. Type \\? for help.\n"},
+		{`\q`, ""},
+	}
+	for _, tt := range tests {
+		h := &history{path: filepath.Join(t.TempDir(), "history")}
+		// Anything after \q is never read.
+		got := run(t, h, tt.input+"\n\\q\n\\?\n")
+		if got != tt.want {
+			t.Errorf("%s printed %q, want %q", tt.input, got, tt.want)
+		}
+	}
+}
+
+func TestHistory(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "history")
+	h, err := loadHistory(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Meta-commands are only recognised at the start of a statement, and
+	// the end of input ends the shell like \q.
+	got := run(t, h, "\\?\n\n\\bogus\n\\s\n")
+	want := helpText + "Unknown command \\bogus. Type \\? for help.\n" +
+		"    1  \\?\n    2  \\bogus\n    3  \\s\n\n"
+	if got != want {
+		t.Errorf("shell printed %q, want %q", got, want)
+	}
+
+	again, err := loadHistory(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fmt.Sprint(again.entries) != fmt.Sprint(h.entries) {
+		t.Errorf("history read back as %q, want %q", again.entries, h.entries)
+	}
+}
@@ -7,6 +7,7 @@ import (
 	"strings"
 	"woojiahao.com/gda/example"
 	"woojiahao.com/gda/internal/setup"
+	"woojiahao.com/gda/internal/shell"
 )
 
 func dispatchExample(eg string) {
@@ -44,12 +45,14 @@ func main() {
 
 	args := os.Args
 	if len(args) < 1 {
-		log.Fatalln("Include the command to run. Commands available: setup, example")
+		log.Fatalln("Include the command to run. Commands available: setup, example, shell")
 	}
 	arg := strings.ToLower(args[1])
 	switch arg {
 	case "setup":
 		setup.Setup()
+	case "shell":
+		shell.Run()
 	case "example":
 		if len(args) < 2 {
 			log.Fatalln("Include the example to run. Examples available: connect, single, multi, parameterised, null, insert, transaction, struct, return, prepared, conn, timeout")
@@ -0,0 +1,145 @@
+package analysis
+
+import (
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// Options controls how a name is analysed.
+type Options struct {
+	Language Language
+
+	// Y overrides the language's rule for y when set.
+	Y *YRule
+}
+
+// DefaultOptions analyses names as English.
+func DefaultOptions() Options {
+	return Options{Language: languages["en"]}
+}
+
+// Letter is one letter of a name.
+type Letter struct {
+	// Rune is the letter as written and Base the lower case letter left once
+	// accents are stripped, so for "É" Rune is 'É' and Base is 'e'.
+	Rune rune `json:"rune"`
+	Base rune `json:"base"`
+
+	// Position is the index of the letter among the characters of the name,
+	// counting spaces and punctuation.
+	Position int  `json:"position"`
+	Word     int  `json:"word"`
+	Vowel    bool `json:"vowel"`
+}
+
+// Result describes the vowels and consonants of a name.
+type Result struct {
+	// Name is the input in NFC form, trimmed of surrounding space. Positions
+	// are rune offsets into it.
+	Name  string   `json:"name"`
+	Words []string `json:"words"`
+
+	Language string `json:"language"`
+	Y        YRule  `json:"y_rule"`
+
+	Vowels             int   `json:"vowels"`
+	Consonants         int   `json:"consonants"`
+	VowelPositions     []int `json:"vowel_positions"`
+	ConsonantPositions []int `json:"consonant_positions"`
+
+	Letters []Letter `json:"letters"`
+}
+
+// HasVowel reports whether the name contains at least one vowel.
+func (r Result) HasVowel() bool {
+	return r.Vowels > 0
+}
+
+// Analyse splits name into letters and classifies each one as a vowel or a
+// consonant. Anything that is not a letter, like spaces, hyphens or
+// apostrophes, is skipped but still counts towards positions.
+func Analyse(name string, opts Options) Result {
+	if opts.Language.Code == "" {
+		opts.Language = languages["en"]
+	}
+	yRule := opts.Language.Y
+	if opts.Y != nil {
+		yRule = *opts.Y
+	}
+
+	name = norm.NFC.String(strings.TrimSpace(name))
+	result := Result{
+		Name:     name,
+		Words:    strings.Fields(name),
+		Language: opts.Language.Code,
+		Y:        yRule,
+	}
+
+	runes := []rune(name)
+	bases := make([]rune, len(runes))
+	for i, r := range runes {
+		bases[i] = base(r)
+	}
+
+	word := 0
+	for i, r := range runes {
+		if unicode.IsSpace(r) {
+			if i > 0 && !unicode.IsSpace(runes[i-1]) {
+				word++
+			}
+			continue
+		}
+		if !unicode.IsLetter(r) {
+			continue
+		}
+
+		letter := Letter{Rune: r, Base: bases[i], Position: i, Word: word}
+		letter.Vowel = isVowel(bases, i, opts.Language, yRule)
+		result.Letters = append(result.Letters, letter)
+
+		if letter.Vowel {
+			result.Vowels++
+			result.VowelPositions = append(result.VowelPositions, i)
+		} else {
+			result.Consonants++
+			result.ConsonantPositions = append(result.ConsonantPositions, i)
+		}
+	}
+	return result
+}
+
+// base strips any accents from r with NFD and lower cases what is left.
+func base(r rune) rune {
+	// The first rune of the decomposition is the base letter, the rest are
+	// combining marks.
+	for _, d := range norm.NFD.String(string(r)) {
+		return unicode.ToLower(d)
+	}
+	return r
+}
+
+// isVowel decides whether the letter at i is a vowel. bases holds the base
+// letter of every rune in the name.
+func isVowel(bases []rune, i int, lang Language, yRule YRule) bool {
+	b := bases[i]
+	if b != 'y' {
+		return strings.ContainsRune(lang.Vowels, b)
+	}
+
+	switch yRule {
+	case YAlways:
+		return true
+	case YNever:
+		return false
+	}
+
+	// A y that starts a syllable acts as a consonant: it is followed by a
+	// vowel and comes at the start of a word or after another vowel, as in
+	// "Yusuf" or "Maya". Anywhere else it carries the vowel sound, as in
+	// "Lynn", "Bryan" or "Mary".
+	followedByVowel := i+1 < len(bases) && strings.ContainsRune(lang.Vowels, bases[i+1])
+	startsSyllable := i == 0 || !unicode.IsLetter(bases[i-1]) || strings.ContainsRune(lang.Vowels, bases[i-1])
+	return !(followedByVowel && startsSyllable)
+}
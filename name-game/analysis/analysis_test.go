@@ -0,0 +1,66 @@
+package analysis
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestAnalyse(t *testing.T) {
+	always, never := YAlways, YNever
+	tests := []struct {
+		name       string
+		lang       string
+		y          *YRule
+		vowels     []int
+		consonants int
+	}{
+		{name: "Ada", vowels: []int{0, 2}, consonants: 1},
+		{name: "Bjørn Øster", vowels: []int{2, 6, 9}, consonants: 7},
+		{name: "Ægir", vowels: []int{0, 2}, consonants: 2},
+		{name: "Œdipe", vowels: []int{0, 2, 4}, consonants: 2},
+		{name: "Zoë Åsa", vowels: []int{1, 2, 4, 6}, consonants: 2},
+		{name: "José-María", vowels: []int{1, 3, 6, 8, 9}, consonants: 4},
+		{name: "Renée", vowels: []int{1, 3, 4}, consonants: 2},
+		{name: "Lynn", vowels: []int{1}, consonants: 3},
+		{name: "Mary", vowels: []int{1, 3}, consonants: 2},
+		{name: "Yusuf", vowels: []int{1, 3}, consonants: 3},
+		{name: "Maya", vowels: []int{1, 3}, consonants: 2},
+		{name: "Yusuf", y: &always, vowels: []int{0, 1, 3}, consonants: 2},
+		{name: "Lynn", y: &never, vowels: nil, consonants: 4},
+		{name: "Gwyn", lang: "cy", vowels: []int{1, 2}, consonants: 2},
+		{name: "Işık", lang: "tr", vowels: []int{0, 2}, consonants: 2},
+		{name: "Nkrm", vowels: nil, consonants: 4},
+	}
+	for _, tt := range tests {
+		opts := DefaultOptions()
+		if tt.lang != "" {
+			lang, err := LookupLanguage(tt.lang)
+			if err != nil {
+				t.Fatal(err)
+			}
+			opts.Language = lang
+		}
+		opts.Y = tt.y
+
+		got := Analyse(tt.name, opts)
+		if !reflect.DeepEqual(got.VowelPositions, tt.vowels) || got.Consonants != tt.consonants {
+			t.Errorf("Analyse(%q, %s) vowels at %v with %d consonants, want %v with %d",
+				tt.name, opts.Language.Code, got.VowelPositions, got.Consonants, tt.vowels, tt.consonants)
+		}
+		if got.Vowels != len(tt.vowels) {
+			t.Errorf("Analyse(%q) counted %d vowels at %d positions", tt.name, got.Vowels, len(got.VowelPositions))
+		}
+	}
+}
+
+func TestAnalyseNormalises(t *testing.T) {
+	// "é" written as e followed by a combining accent is the same name.
+	composed := Analyse("André", DefaultOptions())
+	decomposed := Analyse("  Andre\u0301 ", DefaultOptions())
+	if composed.Name != decomposed.Name || !reflect.DeepEqual(composed.VowelPositions, decomposed.VowelPositions) {
+		t.Errorf("composed %+v and decomposed %+v analyse differently", composed, decomposed)
+	}
+	if last := composed.Letters[len(composed.Letters)-1]; last.Rune != 'é' || last.Base != 'e' || !last.Vowel {
+		t.Errorf("last letter of André = %+v, want é with base e, a vowel", last)
+	}
+}
@@ -0,0 +1,102 @@
+package analysis
+
+import (
+	"fmt"
+	"sort"
+)
+
+// YRule says when the letter y counts as a vowel.
+type YRule int
+
+const (
+	// YContextual treats y as a vowel unless it starts a syllable, that is
+	// when it is followed by a vowel ("Lynn" and "Mary" but not "Yusuf").
+	YContextual YRule = iota
+
+	// YAlways treats y as a vowel everywhere.
+	YAlways
+
+	// YNever treats y as a consonant everywhere.
+	YNever
+)
+
+// Language holds the vowels of one language. Vowels are listed as base
+// letters, after accents have been removed with NFD, so "e" also covers é,
+// è and ê. Letters that do not decompose, like ø or æ, are listed as is.
+type Language struct {
+	Code   string
+	Name   string
+	Vowels string
+
+	// Y is the default rule for y in the language.
+	Y YRule
+}
+
+// languages are the built-in vowel sets, keyed by ISO 639-1 code. English
+// is the default, and names spelt with æ, ø or œ turn up in English text
+// often enough that they count as vowels there too.
+var languages = map[string]Language{
+	"en": {Code: "en", Name: "English", Vowels: "aeiouæøœ", Y: YContextual},
+	"fr": {Code: "fr", Name: "French", Vowels: "aeiouæœ", Y: YAlways},
+	"de": {Code: "de", Name: "German", Vowels: "aeiou", Y: YAlways},
+	"es": {Code: "es", Name: "Spanish", Vowels: "aeiou", Y: YContextual},
+	"it": {Code: "it", Name: "Italian", Vowels: "aeiou", Y: YNever},
+	"pt": {Code: "pt", Name: "Portuguese", Vowels: "aeiou", Y: YContextual},
+	"nl": {Code: "nl", Name: "Dutch", Vowels: "aeiou", Y: YContextual},
+	"da": {Code: "da", Name: "Danish", Vowels: "aeiouæø", Y: YAlways},
+	"no": {Code: "no", Name: "Norwegian", Vowels: "aeiouæø", Y: YAlways},
+	"sv": {Code: "sv", Name: "Swedish", Vowels: "aeiou", Y: YAlways},
+	"fi": {Code: "fi", Name: "Finnish", Vowels: "aeiou", Y: YAlways},
+	"pl": {Code: "pl", Name: "Polish", Vowels: "aeiou", Y: YAlways},
+	"tr": {Code: "tr", Name: "Turkish", Vowels: "aeiouı", Y: YNever},
+	"cy": {Code: "cy", Name: "Welsh", Vowels: "aeiouw", Y: YAlways},
+}
+
+// LookupLanguage returns the built-in language with the given code.
+func LookupLanguage(code string) (Language, error) {
+	lang, ok := languages[code]
+	if !ok {
+		return Language{}, fmt.Errorf("unknown language %q, expected one of %v", code, LanguageCodes())
+	}
+	return lang, nil
+}
+
+// LanguageCodes lists the codes of the built-in languages.
+func LanguageCodes() []string {
+	var codes []string
+	for code := range languages {
+		codes = append(codes, code)
+	}
+	sort.Strings(codes)
+	return codes
+}
+
+// ParseYRule reads a y rule as written on the command line.
+func ParseYRule(s string) (YRule, error) {
+	switch s {
+	case "contextual":
+		return YContextual, nil
+	case "always":
+		return YAlways, nil
+	case "never":
+		return YNever, nil
+	}
+	return 0, fmt.Errorf("unknown y rule %q, expected contextual, always or never", s)
+}
+
+func (r YRule) String() string {
+	switch r {
+	case YContextual:
+		return "contextual"
+	case YAlways:
+		return "always"
+	case YNever:
+		return "never"
+	}
+	return fmt.Sprintf("YRule(%d)", int(r))
+}
+
+// MarshalText lets the rule appear by name in JSON.
+func (r YRule) MarshalText() ([]byte, error) {
+	return []byte(r.String()), nil
+}
@@ -0,0 +1,110 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+
+	"github.com/TheInvincibleRalph/name-game.git/batch"
+)
+
+// analyze runs the analysis over a whole file of names
+func analyze(args []string) {
+	flags := flag.NewFlagSet("analyze", flag.ExitOnError)
+	in := flags.String("in", "-", "file of names, one per line or a CSV with a header (- for stdin)")
+	column := flags.String("column", "name", "CSV column holding the names")
+	inputFormat := flags.String("input", "", "input format: txt or csv (guessed from the file extension if empty)")
+	format := flags.String("format", "csv", "report format: csv or json")
+	out := flags.String("out", "-", "file to write the report to (- for stdout)")
+	workers := flags.Int("workers", runtime.NumCPU(), "number of names to analyse at once")
+	opts := optionFlags(flags)
+	flags.Parse(args)
+
+	if *workers < 1 {
+		log.Fatal("-workers must be at least 1")
+	}
+	options, err := opts()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	var input io.Reader = os.Stdin
+	if *in != "-" {
+		file, err := os.Open(*in)
+		if err != nil {
+			log.Fatal(err)
+		}
+		defer file.Close()
+		input = file
+	}
+
+	if *inputFormat == "" {
+		*inputFormat = "txt"
+		if strings.EqualFold(filepath.Ext(*in), ".csv") {
+			*inputFormat = "csv"
+		}
+	}
+
+	var output io.Writer = os.Stdout
+	if *out != "-" {
+		file, err := os.Create(*out)
+		if err != nil {
+			log.Fatal(err)
+		}
+		defer file.Close()
+		output = file
+	}
+
+	report, err := batch.NewWriter(output, *format)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	//read in the background so names are analysed while the file streams in
+	inputs := make(chan batch.Input, *workers)
+	readErr := make(chan error, 1)
+	go func() {
+		switch *inputFormat {
+		case "csv":
+			readErr <- batch.ReadCSV(input, *column, inputs)
+		case "txt":
+			readErr <- batch.ReadLines(input, inputs)
+		default:
+			close(inputs)
+			readErr <- fmt.Errorf("unknown input format %q, expected txt or csv", *inputFormat)
+		}
+	}()
+
+	agg, err := batch.Analyse(inputs, options, *workers, report.Write)
+	if err != nil {
+		log.Fatal(err)
+	}
+	if err := <-readErr; err != nil {
+		log.Fatalf("reading %s: %v", *in, err)
+	}
+	if err := report.Close(agg); err != nil {
+		log.Fatal(err)
+	}
+
+	if *format == "csv" {
+		printAggregates(agg)
+	}
+}
+
+// printAggregates writes the totals to stderr so they stay out of a CSV report
+func printAggregates(agg batch.Aggregates) {
+	fmt.Fprintf(os.Stderr, "Names:            %d\n", agg.Names)
+	fmt.Fprintf(os.Stderr, "Without a vowel:  %d\n", agg.VowelLess)
+	fmt.Fprintf(os.Stderr, "Vowels:           %d (%.2f per name)\n", agg.TotalVowels, agg.AverageVowels)
+	fmt.Fprintf(os.Stderr, "Consonants:       %d\n", agg.TotalConsonants)
+	fmt.Fprintf(os.Stderr, "Average length:   %.2f\n", agg.AverageLength)
+	if agg.Names > 0 {
+		fmt.Fprintf(os.Stderr, "Longest:          %s\n", agg.Longest)
+		fmt.Fprintf(os.Stderr, "Shortest:         %s\n", agg.Shortest)
+	}
+}
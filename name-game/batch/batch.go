@@ -0,0 +1,224 @@
+// Package batch runs the name analysis over whole files of names. Input is
+// streamed, analysed by a pool of workers and written back out in the
+// original order, so files much larger than memory can be processed.
+package batch
+
+import (
+	"bufio"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/TheInvincibleRalph/name-game.git/analysis"
+)
+
+// Record is the analysis of one name in the input.
+type Record struct {
+	// Line is the line of the input the name came from.
+	Line       int    `json:"line"`
+	Name       string `json:"name"`
+	Length     int    `json:"length"`
+	Vowels     int    `json:"vowels"`
+	Consonants int    `json:"consonants"`
+	VowelLess  bool   `json:"vowel_less"`
+	Initials   string `json:"initials"`
+}
+
+// Aggregates summarise every record of a run.
+type Aggregates struct {
+	Names           int     `json:"names"`
+	VowelLess       int     `json:"vowel_less"`
+	TotalVowels     int     `json:"total_vowels"`
+	TotalConsonants int     `json:"total_consonants"`
+	AverageLength   float64 `json:"average_length"`
+	AverageVowels   float64 `json:"average_vowels"`
+	Longest         string  `json:"longest"`
+	Shortest        string  `json:"shortest"`
+
+	totalLength int
+}
+
+func (a *Aggregates) add(r Record) {
+	if a.Names == 0 || r.Length > utf8.RuneCountInString(a.Longest) {
+		a.Longest = r.Name
+	}
+	if a.Names == 0 || r.Length < utf8.RuneCountInString(a.Shortest) {
+		a.Shortest = r.Name
+	}
+
+	a.Names++
+	a.TotalVowels += r.Vowels
+	a.TotalConsonants += r.Consonants
+	a.totalLength += r.Length
+	if r.VowelLess {
+		a.VowelLess++
+	}
+
+	a.AverageLength = float64(a.totalLength) / float64(a.Names)
+	a.AverageVowels = float64(a.TotalVowels) / float64(a.Names)
+}
+
+// Input is one name read from the input.
+type Input struct {
+	Line int
+	Name string
+}
+
+// ReadLines streams names from a text file with one name per line. Blank
+// lines are skipped.
+func ReadLines(r io.Reader, inputs chan<- Input) error {
+	defer close(inputs)
+
+	scanner := bufio.NewScanner(r)
+	for line := 1; scanner.Scan(); line++ {
+		if name := strings.TrimSpace(scanner.Text()); name != "" {
+			inputs <- Input{Line: line, Name: name}
+		}
+	}
+	return scanner.Err()
+}
+
+// ReadCSV streams names from the given column of a CSV file whose first row
+// is a header.
+func ReadCSV(r io.Reader, column string, inputs chan<- Input) error {
+	defer close(inputs)
+
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+
+	header, err := reader.Read()
+	if errors.Is(err, io.EOF) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	idx := -1
+	for i, c := range header {
+		if strings.TrimSpace(c) == column {
+			idx = i
+		}
+	}
+	if idx < 0 {
+		return fmt.Errorf("no column %q in %v", column, header)
+	}
+
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		line, _ := reader.FieldPos(0)
+		if idx >= len(record) {
+			return fmt.Errorf("line %d: missing column %q", line, column)
+		}
+		if name := strings.TrimSpace(record[idx]); name != "" {
+			inputs <- Input{Line: line, Name: name}
+		}
+	}
+}
+
+// Analyse reads names from inputs until it is closed, analyses them on the
+// given number of workers, at least one, and calls emit with each record in
+// input order. It returns the aggregates over every record.
+func Analyse(inputs <-chan Input, opts analysis.Options, workers int, emit func(Record) error) (Aggregates, error) {
+	workers = max(workers, 1)
+
+	type job struct {
+		seq int
+		in  Input
+	}
+	type done struct {
+		seq    int
+		record Record
+	}
+
+	jobs := make(chan job, workers)
+	results := make(chan done, workers)
+
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := range jobs {
+				results <- done{seq: j.seq, record: analyse(j.in, opts)}
+			}
+		}()
+	}
+
+	go func() {
+		seq := 0
+		for in := range inputs {
+			jobs <- job{seq: seq, in: in}
+			seq++
+		}
+		close(jobs)
+		wg.Wait()
+		close(results)
+	}()
+
+	// Workers finish out of order, so hold on to early records until every
+	// record before them has been emitted.
+	var (
+		agg     Aggregates
+		next    int
+		pending = map[int]Record{}
+		err     error
+	)
+	for d := range results {
+		pending[d.seq] = d.record
+		for {
+			record, ok := pending[next]
+			if !ok {
+				break
+			}
+			delete(pending, next)
+			next++
+
+			agg.add(record)
+			if err == nil {
+				err = emit(record)
+			}
+		}
+	}
+	return agg, err
+}
+
+func analyse(in Input, opts analysis.Options) Record {
+	result := analysis.Analyse(in.Name, opts)
+	return Record{
+		Line:       in.Line,
+		Name:       result.Name,
+		Length:     utf8.RuneCountInString(result.Name),
+		Vowels:     result.Vowels,
+		Consonants: result.Consonants,
+		VowelLess:  !result.HasVowel(),
+		Initials:   initials(result.Words),
+	}
+}
+
+// initials takes the first letter of every word, so "mary-anne o'neil"
+// becomes "MO".
+func initials(words []string) string {
+	var b strings.Builder
+	for _, w := range words {
+		for _, r := range w {
+			if unicode.IsLetter(r) {
+				b.WriteRune(unicode.ToUpper(r))
+				break
+			}
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,181 @@
+package batch
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/TheInvincibleRalph/name-game.git/analysis"
+)
+
+// run feeds names to Analyse and returns the records in the order emitted.
+func run(t *testing.T, names []string, workers int) ([]Record, Aggregates) {
+	t.Helper()
+	inputs := make(chan Input)
+	go func() {
+		for i, name := range names {
+			inputs <- Input{Line: i + 1, Name: name}
+		}
+		close(inputs)
+	}()
+
+	var records []Record
+	agg, err := Analyse(inputs, analysis.DefaultOptions(), workers, func(r Record) error {
+		records = append(records, r)
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return records, agg
+}
+
+func TestAnalyseKeepsOrder(t *testing.T) {
+	var names []string
+	for i := 0; i < 1000; i++ {
+		names = append(names, fmt.Sprintf("%sName %d", strings.Repeat("a", i%7), i))
+	}
+
+	// Worker counts below one are treated as one rather than failing.
+	for _, workers := range []int{-1, 0, 1, 8} {
+		records, agg := run(t, names, workers)
+		if len(records) != len(names) || agg.Names != len(names) {
+			t.Fatalf("workers=%d: %d records and %d names aggregated, want %d", workers, len(records), agg.Names, len(names))
+		}
+		for i, r := range records {
+			if r.Line != i+1 || r.Name != names[i] {
+				t.Fatalf("workers=%d: record %d is line %d %q, want line %d %q", workers, i, r.Line, r.Name, i+1, names[i])
+			}
+		}
+	}
+}
+
+func TestAggregates(t *testing.T) {
+	records, agg := run(t, []string{"Ada Lovelace", "Nkrm", "Bo"}, 2)
+	if records[1].Vowels != 0 || !records[1].VowelLess || records[0].Initials != "AL" {
+		t.Errorf("records = %+v", records)
+	}
+
+	want := Aggregates{
+		Names:           3,
+		VowelLess:       1,
+		TotalVowels:     7,
+		TotalConsonants: 10,
+		AverageLength:   float64(12+4+2) / 3,
+		AverageVowels:   float64(6+0+1) / 3,
+		Longest:         "Ada Lovelace",
+		Shortest:        "Bo",
+		totalLength:     18,
+	}
+	if agg != want {
+		t.Errorf("aggregates = %+v, want %+v", agg, want)
+	}
+}
+
+func TestAnalyseStopsEmittingAfterError(t *testing.T) {
+	inputs := make(chan Input, 3)
+	for _, name := range []string{"a", "b", "c"} {
+		inputs <- Input{Name: name}
+	}
+	close(inputs)
+
+	calls := 0
+	agg, err := Analyse(inputs, analysis.DefaultOptions(), 2, func(Record) error {
+		calls++
+		return fmt.Errorf("disk full")
+	})
+	if err == nil || calls != 1 {
+		t.Errorf("err = %v after %d calls, want the first error and no more calls", err, calls)
+	}
+	if agg.Names != 3 {
+		t.Errorf("aggregated %d names, want all 3", agg.Names)
+	}
+}
+
+func read(t *testing.T, fn func(chan<- Input) error) []Input {
+	t.Helper()
+	inputs := make(chan Input)
+	errc := make(chan error, 1)
+	go func() { errc <- fn(inputs) }()
+
+	var got []Input
+	for in := range inputs {
+		got = append(got, in)
+	}
+	if err := <-errc; err != nil {
+		t.Fatal(err)
+	}
+	return got
+}
+
+func TestReadLines(t *testing.T) {
+	got := read(t, func(inputs chan<- Input) error {
+		return ReadLines(strings.NewReader("Ada\n\n  Grace Hopper  \n"), inputs)
+	})
+	want := []Input{{Line: 1, Name: "Ada"}, {Line: 3, Name: "Grace Hopper"}}
+	if fmt.Sprint(got) != fmt.Sprint(want) {
+		t.Errorf("ReadLines = %v, want %v", got, want)
+	}
+}
+
+func TestReadCSV(t *testing.T) {
+	const input = "id,name\n1,Ada\n2,\n3,\"Hopper, Grace\"\n"
+	got := read(t, func(inputs chan<- Input) error {
+		return ReadCSV(strings.NewReader(input), "name", inputs)
+	})
+	want := []Input{{Line: 2, Name: "Ada"}, {Line: 4, Name: "Hopper, Grace"}}
+	if fmt.Sprint(got) != fmt.Sprint(want) {
+		t.Errorf("ReadCSV = %v, want %v", got, want)
+	}
+
+	inputs := make(chan Input, 10)
+	if err := ReadCSV(strings.NewReader(input), "surname", inputs); err == nil {
+		t.Error("ReadCSV found a column that is not there")
+	}
+	if err := ReadCSV(strings.NewReader("id,name\n1\n"), "name", make(chan Input, 10)); err == nil {
+		t.Error("ReadCSV accepted a row without the name column")
+	}
+}
+
+func TestWriters(t *testing.T) {
+	records, agg := run(t, []string{"Ada", "Nkrm"}, 1)
+
+	var csvOut strings.Builder
+	w, _ := NewWriter(&csvOut, "csv")
+	for _, r := range records {
+		w.Write(r)
+	}
+	if err := w.Close(agg); err != nil {
+		t.Fatal(err)
+	}
+	const wantCSV = "line,name,length,vowels,consonants,vowel_less,initials\n1,Ada,3,2,1,false,A\n2,Nkrm,4,0,4,true,N\n"
+	if csvOut.String() != wantCSV {
+		t.Errorf("csv report:\n%s\nwant:\n%s", csvOut.String(), wantCSV)
+	}
+
+	for _, n := range []int{0, 2} {
+		var jsonOut strings.Builder
+		w, _ = NewWriter(&jsonOut, "json")
+		for _, r := range records[:n] {
+			w.Write(r)
+		}
+		if err := w.Close(agg); err != nil {
+			t.Fatal(err)
+		}
+		var report struct {
+			Records    []Record   `json:"records"`
+			Aggregates Aggregates `json:"aggregates"`
+		}
+		if err := json.Unmarshal([]byte(jsonOut.String()), &report); err != nil {
+			t.Fatalf("json report with %d records is not valid JSON: %v\n%s", n, err, jsonOut.String())
+		}
+		if len(report.Records) != n || report.Aggregates.Names != agg.Names {
+			t.Errorf("json report with %d records decoded to %+v", n, report)
+		}
+	}
+
+	if _, err := NewWriter(&strings.Builder{}, "xml"); err == nil {
+		t.Error("NewWriter accepted an unknown format")
+	}
+}
@@ -0,0 +1,97 @@
+package batch
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+)
+
+// Writer writes records as they are analysed and the aggregates once the
+// input is done.
+type Writer interface {
+	Write(Record) error
+	Close(Aggregates) error
+}
+
+// NewWriter returns a writer for the given format, csv or json.
+func NewWriter(w io.Writer, format string) (Writer, error) {
+	switch format {
+	case "csv":
+		return newCSVWriter(w), nil
+	case "json":
+		return &jsonWriter{w: w}, nil
+	}
+	return nil, fmt.Errorf("unknown format %q, expected csv or json", format)
+}
+
+// csvWriter writes one row per name. CSV has no room for the aggregates, so
+// they are left to the caller.
+type csvWriter struct {
+	w *csv.Writer
+}
+
+func newCSVWriter(w io.Writer) *csvWriter {
+	writer := csv.NewWriter(w)
+	writer.Write([]string{"line", "name", "length", "vowels", "consonants", "vowel_less", "initials"})
+	return &csvWriter{w: writer}
+}
+
+func (c *csvWriter) Write(r Record) error {
+	return c.w.Write([]string{
+		strconv.Itoa(r.Line),
+		r.Name,
+		strconv.Itoa(r.Length),
+		strconv.Itoa(r.Vowels),
+		strconv.Itoa(r.Consonants),
+		strconv.FormatBool(r.VowelLess),
+		r.Initials,
+	})
+}
+
+func (c *csvWriter) Close(Aggregates) error {
+	c.w.Flush()
+	return c.w.Error()
+}
+
+// jsonWriter streams an object of the form
+//
+//	{"records": [...], "aggregates": {...}}
+//
+// writing each record as it arrives rather than holding them all.
+type jsonWriter struct {
+	w       io.Writer
+	started bool
+}
+
+func (j *jsonWriter) Write(r Record) error {
+	prefix := ",\n    "
+	if !j.started {
+		prefix = "{\n  \"records\": [\n    "
+		j.started = true
+	}
+	data, err := json.Marshal(r)
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(j.w, "%s%s", prefix, data)
+	return err
+}
+
+func (j *jsonWriter) Close(agg Aggregates) error {
+	if !j.started {
+		if _, err := io.WriteString(j.w, "{\n  \"records\": ["); err != nil {
+			return err
+		}
+	} else if _, err := io.WriteString(j.w, "\n  "); err != nil {
+		return err
+	}
+
+	data, err := json.MarshalIndent(agg, "  ", "  ")
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(j.w, "],\n  \"aggregates\": %s\n}\n", data)
+	return err
+}
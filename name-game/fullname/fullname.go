@@ -0,0 +1,163 @@
+// Package fullname splits personal names into their parts: titles, given
+// and middle names, family name and suffixes.
+//
+// Names are read in the Western order "Given Middle Family" unless they are
+// written "Family, Given Middle". Family names keep their particles, so
+// "Ludwig van Beethoven" has the family name "van Beethoven", and hyphens
+// and apostrophes stay part of the name they are in.
+package fullname
+
+import (
+	"strings"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// Name is a parsed personal name. Parts the name does not have are empty.
+type Name struct {
+	Title  string `json:"title"`
+	Given  string `json:"given"`
+	Middle string `json:"middle"`
+	Family string `json:"family"`
+	Suffix string `json:"suffix"`
+}
+
+// Full joins the parts back together in Western order.
+func (n Name) Full() string {
+	var parts []string
+	for _, p := range []string{n.Title, n.Given, n.Middle, n.Family, n.Suffix} {
+		if p != "" {
+			parts = append(parts, p)
+		}
+	}
+	return strings.Join(parts, " ")
+}
+
+// Parse splits name into its parts.
+func Parse(name string) Name {
+	name = norm.NFC.String(strings.Join(strings.Fields(name), " "))
+
+	// Commas either follow the family name or come before a suffix, as in
+	// "O'Neil, Mary" or "Mary O'Neil, Jr.". Take trailing suffixes off first
+	// so they are not mistaken for the given names.
+	parts := strings.Split(name, ",")
+	for i := range parts {
+		parts[i] = strings.TrimSpace(parts[i])
+	}
+	var suffixes []string
+	for len(parts) > 1 && isSuffixes(parts[len(parts)-1]) {
+		suffixes = append([]string{parts[len(parts)-1]}, suffixes...)
+		parts = parts[:len(parts)-1]
+	}
+
+	var n Name
+	if len(parts) > 1 {
+		n = parseInverted(strings.Fields(parts[0]), strings.Fields(strings.Join(parts[1:], " ")))
+	} else {
+		n = parseWestern(strings.Fields(parts[0]))
+		// A lone name before a suffix is a family name, as in "Smith, Jr.".
+		if n.Family == "" && len(suffixes) > 0 {
+			n.Given, n.Family = "", n.Given
+		}
+	}
+
+	n.Suffix = strings.Join(append(strings.Fields(n.Suffix), suffixes...), " ")
+	return n
+}
+
+// parseWestern reads "Title Given Middle Family Suffix".
+func parseWestern(words []string) Name {
+	var n Name
+	words, n.Title = takeTitles(words)
+	words, n.Suffix = takeSuffixes(words, 1)
+
+	switch len(words) {
+	case 0:
+		return n
+	case 1:
+		// "Mr. Smith" and "Smith Jr." are family names but "Smith" alone is
+		// taken as given.
+		if n.Title != "" || n.Suffix != "" {
+			n.Family = words[0]
+		} else {
+			n.Given = words[0]
+		}
+		return n
+	}
+
+	// The family name is the last word and any particles just before it.
+	// The first word is a given name, as in "Van Morrison", unless it is a
+	// lower case particle or follows a title, as in "de la Cruz" or
+	// "Dr. Van Helsing".
+	start := len(words) - 1
+	for start > 0 && isParticle(words[start-1]) {
+		start--
+	}
+	if start == 0 && n.Title == "" && !startsLower(words[0]) {
+		start = 1
+	}
+	if start == 0 {
+		n.Family = strings.Join(words, " ")
+		return n
+	}
+
+	n.Given = words[0]
+	n.Middle = strings.Join(words[1:start], " ")
+	n.Family = strings.Join(words[start:], " ")
+	return n
+}
+
+// parseInverted reads "Family Suffix, Title Given Middle Suffix".
+func parseInverted(family, given []string) Name {
+	var n Name
+	var suffix string
+	family, suffix = takeSuffixes(family, 1)
+
+	given, n.Title = takeTitles(given)
+	given, n.Suffix = takeSuffixes(given, 1)
+	n.Suffix = strings.TrimSpace(suffix + " " + n.Suffix)
+
+	n.Family = strings.Join(family, " ")
+	if len(given) > 0 {
+		n.Given = given[0]
+		n.Middle = strings.Join(given[1:], " ")
+	}
+	return n
+}
+
+// takeTitles removes the titles at the start of words.
+func takeTitles(words []string) (rest []string, title string) {
+	i := 0
+	for i < len(words)-1 && isTitle(words[i]) {
+		i++
+	}
+	// A lone title is kept as one too, as in "Dr." on its own.
+	if len(words) == 1 && isTitle(words[0]) {
+		i = 1
+	}
+	return words[i:], strings.Join(words[:i], " ")
+}
+
+// takeSuffixes removes the suffixes at the end of words, keeping at least
+// keep words for the name itself.
+func takeSuffixes(words []string, keep int) (rest []string, suffix string) {
+	i := len(words)
+	for i > keep && isSuffix(words[i-1], i-1 > keep) {
+		i--
+	}
+	// A lone suffix is kept as one too, as in "Jr." on its own.
+	if len(words) == 1 && isSuffix(words[0], false) {
+		i = 0
+	}
+	return words[:i], strings.TrimSuffix(strings.Join(words[i:], " "), ",")
+}
+
+func isSuffixes(s string) bool {
+	words := strings.Fields(s)
+	for _, w := range words {
+		if !isSuffix(w, true) {
+			return false
+		}
+	}
+	return len(words) > 0
+}
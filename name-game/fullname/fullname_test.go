@@ -0,0 +1,107 @@
+package fullname
+
+import "testing"
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name string
+		want Name
+	}{
+		// Western order.
+		{"John", Name{Given: "John"}},
+		{"John Smith", Name{Given: "John", Family: "Smith"}},
+		{"John Ronald Reuel Tolkien", Name{Given: "John", Middle: "Ronald Reuel", Family: "Tolkien"}},
+		{"  Mary   Ann  Evans ", Name{Given: "Mary", Middle: "Ann", Family: "Evans"}},
+		{"Chinua Achebe", Name{Given: "Chinua", Family: "Achebe"}},
+		{"Ngozi Okonjo-Iweala", Name{Given: "Ngozi", Family: "Okonjo-Iweala"}},
+		{"Mary-Kate O'Neil", Name{Given: "Mary-Kate", Family: "O'Neil"}},
+		{"Sinéad O'Connor", Name{Given: "Sinéad", Family: "O'Connor"}},
+		{"J. R. R. Tolkien", Name{Given: "J.", Middle: "R. R.", Family: "Tolkien"}},
+		{"Ii Naosuke", Name{Given: "Ii", Family: "Naosuke"}},
+		{"Malcolm X", Name{Given: "Malcolm", Family: "X"}},
+
+		// Titles.
+		{"Dr. Jane Goodall", Name{Title: "Dr.", Given: "Jane", Family: "Goodall"}},
+		{"Mr. Smith", Name{Title: "Mr.", Family: "Smith"}},
+		{"Prof. Dr. Angela Merkel", Name{Title: "Prof. Dr.", Given: "Angela", Family: "Merkel"}},
+		{"Alhaji Aliko Dangote", Name{Title: "Alhaji", Given: "Aliko", Family: "Dangote"}},
+		{"Sir Patrick Stewart", Name{Title: "Sir", Given: "Patrick", Family: "Stewart"}},
+		{"Rev Martin Luther King Jr", Name{Title: "Rev", Given: "Martin", Middle: "Luther", Family: "King", Suffix: "Jr"}},
+		{"Dr.", Name{Title: "Dr."}},
+
+		// Suffixes.
+		{"Martin Luther King Jr.", Name{Given: "Martin", Middle: "Luther", Family: "King", Suffix: "Jr."}},
+		{"Martin Luther King, Jr.", Name{Given: "Martin", Middle: "Luther", Family: "King", Suffix: "Jr."}},
+		{"John Smith III", Name{Given: "John", Family: "Smith", Suffix: "III"}},
+		{"John Smith, PhD, MBA", Name{Given: "John", Family: "Smith", Suffix: "PhD MBA"}},
+		{"Jane Doe Ph.D.", Name{Given: "Jane", Family: "Doe", Suffix: "Ph.D."}},
+		{"Henry Ford II", Name{Given: "Henry", Family: "Ford", Suffix: "II"}},
+		{"John V", Name{Given: "John", Family: "V"}},
+		{"Smith Jr.", Name{Family: "Smith", Suffix: "Jr."}},
+		{"Smith, Jr.", Name{Family: "Smith", Suffix: "Jr."}},
+		{"Smith, III", Name{Family: "Smith", Suffix: "III"}},
+		{"Mr. Smith, Esq.", Name{Title: "Mr.", Family: "Smith", Suffix: "Esq."}},
+		{"Jr.", Name{Suffix: "Jr."}},
+		{"Jr., Sr.", Name{Suffix: "Jr. Sr."}},
+
+		// Particles.
+		{"Ludwig van Beethoven", Name{Given: "Ludwig", Family: "van Beethoven"}},
+		{"Vincent Willem van Gogh", Name{Given: "Vincent", Middle: "Willem", Family: "van Gogh"}},
+		{"Juan de la Cruz", Name{Given: "Juan", Family: "de la Cruz"}},
+		{"Charles de Gaulle", Name{Given: "Charles", Family: "de Gaulle"}},
+		{"Mohammed bin Salman", Name{Given: "Mohammed", Family: "bin Salman"}},
+		{"Leonardo da Vinci", Name{Given: "Leonardo", Family: "da Vinci"}},
+		{"Otto von Bismarck", Name{Given: "Otto", Family: "von Bismarck"}},
+		{"Van Morrison", Name{Given: "Van", Family: "Morrison"}},
+		{"Van der Berg", Name{Given: "Van", Family: "der Berg"}},
+		{"de la Cruz", Name{Family: "de la Cruz"}},
+		{"van Gogh", Name{Family: "van Gogh"}},
+		{"Dr. Van Helsing", Name{Title: "Dr.", Family: "Van Helsing"}},
+		{"Mr. de la Cruz", Name{Title: "Mr.", Family: "de la Cruz"}},
+		{"de la Cruz, Jr.", Name{Family: "de la Cruz", Suffix: "Jr."}},
+		{"Ludwig van Beethoven II", Name{Given: "Ludwig", Family: "van Beethoven", Suffix: "II"}},
+
+		// Family name first.
+		{"Smith, John", Name{Given: "John", Family: "Smith"}},
+		{"O'Neil, Mary Kate", Name{Given: "Mary", Middle: "Kate", Family: "O'Neil"}},
+		{"Smith, Dr. John", Name{Title: "Dr.", Given: "John", Family: "Smith"}},
+		{"Smith, John, Jr.", Name{Given: "John", Family: "Smith", Suffix: "Jr."}},
+		{"Smith Jr., John", Name{Given: "John", Family: "Smith", Suffix: "Jr."}},
+		{"van Beethoven, Ludwig", Name{Given: "Ludwig", Family: "van Beethoven"}},
+		{"de la Cruz, Juan Carlos", Name{Given: "Juan", Middle: "Carlos", Family: "de la Cruz"}},
+		{"King, Martin Luther, Jr.", Name{Given: "Martin", Middle: "Luther", Family: "King", Suffix: "Jr."}},
+		{"Smith, Mr.", Name{Title: "Mr.", Family: "Smith"}},
+		{"Tolkien, J. R. R.", Name{Given: "J.", Middle: "R. R.", Family: "Tolkien"}},
+
+		// Unicode is normalized, so composed and decomposed accents agree.
+		{"Zo\u00eb B\u00e1thory", Name{Given: "Zoë", Family: "Báthory"}},
+		{"Zoe\u0308 Ba\u0301thory", Name{Given: "Zoë", Family: "Báthory"}},
+		{"Jürgen Müller", Name{Given: "Jürgen", Family: "Müller"}},
+		{"Ørjan Ødegård", Name{Given: "Ørjan", Family: "Ødegård"}},
+
+		{"", Name{}},
+		{"   ", Name{}},
+	}
+	for _, tt := range tests {
+		if got := Parse(tt.name); got != tt.want {
+			t.Errorf("Parse(%q) = %+v, want %+v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestFull(t *testing.T) {
+	tests := []struct {
+		name, want string
+	}{
+		{"Smith, Dr. John, Jr.", "Dr. John Smith Jr."},
+		{"de la Cruz, Juan", "Juan de la Cruz"},
+		{"Smith, Jr.", "Smith Jr."},
+		{"Jr.", "Jr."},
+		{"", ""},
+	}
+	for _, tt := range tests {
+		if got := Parse(tt.name).Full(); got != tt.want {
+			t.Errorf("Parse(%q).Full() = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
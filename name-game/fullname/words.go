@@ -0,0 +1,70 @@
+package fullname
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// titles are honorifics that come before a name, without their dots.
+var titles = set(
+	"mr", "mrs", "ms", "miss", "mx", "master", "madam", "madame", "mme", "mlle",
+	"dr", "prof", "professor", "sir", "dame", "lord", "lady",
+	"rev", "revd", "reverend", "fr", "father", "pastor", "rabbi", "imam", "sheikh",
+	"hon", "judge", "justice", "capt", "captain", "col", "gen", "lt", "maj", "sgt", "cmdr", "adm",
+	"chief", "alhaji", "alhaja", "engr", "barr", "herr", "frau", "don", "doña", "señor", "señora",
+)
+
+// suffixes are generational and professional suffixes, without their dots.
+var suffixes = set(
+	"jr", "jnr", "sr", "snr", "junior", "senior",
+	"phd", "md", "dds", "esq", "mba", "cpa", "rn", "obe", "mbe", "cbe", "kbe", "qc", "kc",
+)
+
+// romanNumerals are generational suffixes that could also be initials, so
+// they only count as suffixes when there are other words before them.
+var romanNumerals = set("ii", "iii", "iv", "v", "vi")
+
+// particles are the small words that belong to the family name that
+// follows them, as in "van Beethoven", "de la Cruz" or "bin Salman".
+var particles = set(
+	"van", "von", "der", "den", "de", "del", "della", "dei", "di", "da", "das", "dos", "do",
+	"du", "des", "la", "le", "lo", "los", "las", "ter", "ten", "zu", "af", "av",
+	"bin", "binti", "bint", "ibn", "ben", "bat", "al", "el", "abu", "st", "saint", "ap",
+)
+
+func set(words ...string) map[string]bool {
+	m := make(map[string]bool, len(words))
+	for _, w := range words {
+		m[w] = true
+	}
+	return m
+}
+
+// key lower cases a word and drops its dots and commas, so "Ph.D.," and
+// "phd" are the same word.
+func key(word string) string {
+	return strings.ToLower(strings.NewReplacer(".", "", ",", "").Replace(word))
+}
+
+func isTitle(word string) bool {
+	return titles[key(word)]
+}
+
+// isSuffix reports whether word is a suffix. Roman numerals are only
+// accepted if roman is true.
+func isSuffix(word string, roman bool) bool {
+	k := key(word)
+	return suffixes[k] || (roman && romanNumerals[k])
+}
+
+func isParticle(word string) bool {
+	return particles[key(word)]
+}
+
+// startsLower reports whether word starts with a lower case letter, as
+// particles do when they are not the start of a given name.
+func startsLower(word string) bool {
+	r, _ := utf8.DecodeRuneInString(word)
+	return unicode.IsLower(r)
+}
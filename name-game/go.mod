@@ -0,0 +1,5 @@
+module github.com/TheInvincibleRalph/name-game.git
+
+go 1.22.1
+
+require golang.org/x/text v0.14.0
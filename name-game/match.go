@@ -0,0 +1,119 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/TheInvincibleRalph/name-game.git/batch"
+	"github.com/TheInvincibleRalph/name-game.git/match"
+)
+
+// matchNames lists the names in a file that are likely the same person as
+// the one given on the command line
+func matchNames(args []string) {
+	flags := flag.NewFlagSet("match", flag.ExitOnError)
+	against := flags.String("against", "", "file of names to search, one per line or a CSV with a header (- for stdin)")
+	column := flags.String("column", "name", "CSV column holding the names")
+	inputFormat := flags.String("input", "", "input format: txt or csv (guessed from the file extension if empty)")
+	threshold := flags.Float64("threshold", 0.85, "lowest Jaro-Winkler similarity to list, from 0 to 1")
+	phoneticOnly := flags.Bool("phonetic", false, "also list names below the threshold that sound alike under every encoding")
+	asJSON := flags.Bool("json", false, "print the matches as JSON")
+	positional := parseInterspersed(flags, args)
+
+	if len(positional) != 1 || *against == "" {
+		fmt.Fprintln(os.Stderr, `usage: name-game match "Jon Doe" --against names.txt [--threshold 0.85]`)
+		flags.PrintDefaults()
+		os.Exit(2)
+	}
+
+	var input io.Reader = os.Stdin
+	if *against != "-" {
+		file, err := os.Open(*against)
+		if err != nil {
+			log.Fatal(err)
+		}
+		defer file.Close()
+		input = file
+	}
+
+	if *inputFormat == "" {
+		*inputFormat = "txt"
+		if strings.EqualFold(filepath.Ext(*against), ".csv") {
+			*inputFormat = "csv"
+		}
+	}
+
+	inputs := make(chan batch.Input)
+	readErr := make(chan error, 1)
+	go func() {
+		switch *inputFormat {
+		case "csv":
+			readErr <- batch.ReadCSV(input, *column, inputs)
+		case "txt":
+			readErr <- batch.ReadLines(input, inputs)
+		default:
+			close(inputs)
+			readErr <- fmt.Errorf("unknown input format %q, expected txt or csv", *inputFormat)
+		}
+	}()
+
+	matcher := match.NewMatcher(positional[0])
+	var matches []match.Candidate
+	for in := range inputs {
+		c := matcher.Compare(in.Name)
+		c.Line = in.Line
+		if c.Score >= *threshold || (*phoneticOnly && c.SoundsAlike()) {
+			matches = append(matches, c)
+		}
+	}
+	if err := <-readErr; err != nil {
+		log.Fatalf("reading %s: %v", *against, err)
+	}
+	match.Sort(matches)
+
+	if *asJSON {
+		if matches == nil {
+			matches = []match.Candidate{}
+		}
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(matches); err != nil {
+			log.Fatal(err)
+		}
+		return
+	}
+
+	if len(matches) == 0 {
+		fmt.Println("No likely duplicates found.")
+		return
+	}
+	table := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(table, "SCORE\tNAME\tLINE\tEDITS\tSOUNDS ALIKE")
+	for _, c := range matches {
+		fmt.Fprintf(table, "%.3f\t%s\t%d\t%d\t%s\n", c.Score, c.Name, c.Line, c.Distance, strings.Join(c.Phonetic, ", "))
+	}
+	table.Flush()
+}
+
+// parseInterspersed parses flags given before or after the positional
+// arguments, so both `match "Jon Doe" --against f` and
+// `match --against f "Jon Doe"` work. It returns the positional arguments.
+func parseInterspersed(flags *flag.FlagSet, args []string) []string {
+	var positional []string
+	for {
+		flags.Parse(args)
+		args = flags.Args()
+		if len(args) == 0 {
+			return positional
+		}
+		positional = append(positional, args[0])
+		args = args[1:]
+	}
+}
@@ -0,0 +1,122 @@
+package match
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/TheInvincibleRalph/name-game.git/phonetic"
+)
+
+// encoding is one phonetic code names are compared on. Some encodings give
+// more than one code for a word.
+type encoding struct {
+	name   string
+	encode func(word string) []string
+}
+
+var encodings = []encoding{
+	{"soundex", func(w string) []string { return []string{phonetic.Soundex(w)} }},
+	{"metaphone", func(w string) []string {
+		primary, alternate := phonetic.DoubleMetaphone(w)
+		return []string{primary, alternate}
+	}},
+	{"nysiis", func(w string) []string { return []string{phonetic.NYSIIS(w)} }},
+}
+
+// Candidate is a name compared against the query.
+type Candidate struct {
+	Line int    `json:"line,omitempty"`
+	Name string `json:"name"`
+
+	// Score is the Jaro-Winkler similarity of the normalized names and
+	// Distance their Levenshtein distance.
+	Score    float64 `json:"score"`
+	Distance int     `json:"distance"`
+
+	// Phonetic lists the encodings under which every word of the name
+	// sounds like the matching word of the query.
+	Phonetic []string `json:"phonetic"`
+}
+
+// Matcher compares names against one query name.
+type Matcher struct {
+	query string
+	words [][][]string
+}
+
+// NewMatcher returns a matcher for query, encoding it once up front.
+func NewMatcher(query string) *Matcher {
+	query = phonetic.Normalize(query)
+	return &Matcher{query: query, words: encodeWords(query)}
+}
+
+// encodeWords returns the codes of every word of name under every encoding,
+// indexed by encoding and then by word.
+func encodeWords(name string) [][][]string {
+	words := strings.Fields(name)
+	codes := make([][][]string, len(encodings))
+	for i, e := range encodings {
+		for _, w := range words {
+			codes[i] = append(codes[i], e.encode(w))
+		}
+	}
+	return codes
+}
+
+// Compare scores name against the query.
+func (m *Matcher) Compare(name string) Candidate {
+	normalized := phonetic.Normalize(name)
+	c := Candidate{
+		Name:     name,
+		Score:    JaroWinkler(m.query, normalized),
+		Distance: Levenshtein(m.query, normalized),
+		Phonetic: []string{},
+	}
+
+	words := encodeWords(normalized)
+	for i, e := range encodings {
+		if soundAlike(m.words[i], words[i]) {
+			c.Phonetic = append(c.Phonetic, e.name)
+		}
+	}
+	return c
+}
+
+// soundAlike reports whether two names have the same number of words and
+// each pair of words shares a code.
+func soundAlike(a, b [][]string) bool {
+	if len(a) == 0 || len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if !shareCode(a[i], b[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+func shareCode(a, b []string) bool {
+	for _, x := range a {
+		for _, y := range b {
+			if x != "" && x == y {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// SoundsAlike reports whether the name sounds like the query under every
+// encoding.
+func (c Candidate) SoundsAlike() bool {
+	return len(c.Phonetic) == len(encodings)
+}
+
+// Sort orders candidates from the best match down, keeping input order for
+// equal scores.
+func Sort(candidates []Candidate) {
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return candidates[i].Score > candidates[j].Score
+	})
+}
@@ -0,0 +1,155 @@
+package match
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"testing"
+	"unicode/utf8"
+)
+
+func TestLevenshtein(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{"kitten", "sitting", 3},
+		{"saturday", "sunday", 3},
+		{"flaw", "lawn", 2},
+		{"sitting", "kitten", 3},
+		{"", "", 0},
+		{"abc", "", 3},
+		{"", "abc", 3},
+		{"Zoë", "Zoe", 1},
+		{"Zoë", "Zoë", 0},
+	}
+	for _, tt := range tests {
+		if got := Levenshtein(tt.a, tt.b); got != tt.want {
+			t.Errorf("Levenshtein(%q, %q) = %d, want %d", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
+
+// The Jaro-Winkler vectors are those of Winkler's original paper, to three
+// decimal places.
+func TestJaroWinkler(t *testing.T) {
+	tests := []struct {
+		a, b       string
+		jaro, want float64
+	}{
+		{"MARTHA", "MARHTA", 0.944, 0.961},
+		{"DWAYNE", "DUANE", 0.822, 0.840},
+		{"DIXON", "DICKSONX", 0.767, 0.813},
+		{"JELLYFISH", "SMELLYFISH", 0.896, 0.896},
+		{"CRATE", "TRACE", 0.733, 0.733},
+		{"ABC", "XYZ", 0, 0},
+		{"", "", 1, 1},
+		{"A", "", 0, 0},
+		{"SAME", "SAME", 1, 1},
+	}
+	for _, tt := range tests {
+		if got := Jaro(tt.a, tt.b); math.Abs(got-tt.jaro) > 0.0005 {
+			t.Errorf("Jaro(%q, %q) = %.4f, want %.3f", tt.a, tt.b, got, tt.jaro)
+		}
+		if got := JaroWinkler(tt.a, tt.b); math.Abs(got-tt.want) > 0.0005 {
+			t.Errorf("JaroWinkler(%q, %q) = %.4f, want %.3f", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
+
+func TestCompare(t *testing.T) {
+	m := NewMatcher("Jon Smith")
+
+	tests := []struct {
+		name     string
+		phonetic string
+	}{
+		{"John Smith", "soundex metaphone nysiis"},
+		{"John Smyth", "soundex metaphone"},
+		{"Jean Schmidt", "soundex metaphone"},
+		{"Jon", ""},
+		{"Mary Jones", ""},
+	}
+	for _, tt := range tests {
+		c := m.Compare(tt.name)
+		if got := strings.Join(c.Phonetic, " "); got != tt.phonetic {
+			t.Errorf("Compare(%q) sounds alike under %q, want %q", tt.name, got, tt.phonetic)
+		}
+		if c.SoundsAlike() != (tt.phonetic == "soundex metaphone nysiis") {
+			t.Errorf("Compare(%q).SoundsAlike() = %t", tt.name, c.SoundsAlike())
+		}
+	}
+
+	candidates := []Candidate{m.Compare("Mary Jones"), m.Compare("John Smyth"), m.Compare("Jon Smith")}
+	Sort(candidates)
+	var order []string
+	for _, c := range candidates {
+		order = append(order, c.Name)
+	}
+	if got := fmt.Sprint(order); got != "[Jon Smith John Smyth Mary Jones]" {
+		t.Errorf("sorted candidates %s, want the exact match first", got)
+	}
+}
+
+func FuzzLevenshtein(f *testing.F) {
+	f.Add("kitten", "sitting")
+	f.Add("Zoë", "Zoe")
+	f.Add("", "abc")
+	f.Fuzz(func(t *testing.T, a, b string) {
+		d := Levenshtein(a, b)
+		if d != Levenshtein(b, a) {
+			t.Errorf("Levenshtein(%q, %q) = %d but the other way round is %d", a, b, d, Levenshtein(b, a))
+		}
+		la, lb := utf8.RuneCountInString(a), utf8.RuneCountInString(b)
+		if d < max(la, lb)-min(la, lb) || d > max(la, lb) {
+			t.Errorf("Levenshtein(%q, %q) = %d, outside the bounds set by their lengths %d and %d", a, b, d, la, lb)
+		}
+		// Levenshtein compares runes, so invalid bytes all count as U+FFFD.
+		if (d == 0) != (string([]rune(a)) == string([]rune(b))) {
+			t.Errorf("Levenshtein(%q, %q) = %d, want 0 exactly for equal strings", a, b, d)
+		}
+	})
+}
+
+func FuzzJaroWinkler(f *testing.F) {
+	f.Add("MARTHA", "MARHTA")
+	f.Add("DIXON", "DICKSONX")
+	f.Add("", "A")
+	f.Fuzz(func(t *testing.T, a, b string) {
+		jaro, jw := Jaro(a, b), JaroWinkler(a, b)
+		if jaro < 0 || jw < jaro || jw > 1 {
+			t.Errorf("Jaro(%q, %q) = %v and JaroWinkler %v, want 0 <= Jaro <= JaroWinkler <= 1", a, b, jaro, jw)
+		}
+		if JaroWinkler(a, a) != 1 {
+			t.Errorf("JaroWinkler(%q, %q) = %v, want 1", a, a, JaroWinkler(a, a))
+		}
+	})
+}
+
+var benchmarkPairs = [][2]string{
+	{"MARTHA", "MARHTA"},
+	{"JONATHAN SMITH", "JOHN SMYTH"},
+	{"MARIA JOSE CARRENO QUINONES", "MARIA CARRENO"},
+	{"ALEKSANDR SOLZHENITSYN", "ALEXANDER SOLZHENITSYN"},
+}
+
+func BenchmarkLevenshtein(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		p := benchmarkPairs[i%len(benchmarkPairs)]
+		Levenshtein(p[0], p[1])
+	}
+}
+
+func BenchmarkJaroWinkler(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		p := benchmarkPairs[i%len(benchmarkPairs)]
+		JaroWinkler(p[0], p[1])
+	}
+}
+
+func BenchmarkCompare(b *testing.B) {
+	m := NewMatcher("Jonathan Smith")
+	for i := 0; i < b.N; i++ {
+		m.Compare(benchmarkPairs[i%len(benchmarkPairs)][1])
+	}
+}
@@ -0,0 +1,101 @@
+// Package match finds names that are likely to belong to the same person,
+// using string similarity and phonetic codes.
+package match
+
+// Levenshtein returns the number of single letter insertions, deletions and
+// substitutions needed to turn a into b. It counts runes, not bytes.
+func Levenshtein(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	if len(ra) < len(rb) {
+		ra, rb = rb, ra
+	}
+
+	// Only the previous row of the table is needed to fill in the next one.
+	prev := make([]int, len(rb)+1)
+	curr := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+
+	for i := 1; i <= len(ra); i++ {
+		curr[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+		prev, curr = curr, prev
+	}
+	return prev[len(rb)]
+}
+
+// Jaro returns the Jaro similarity of a and b, from 0 for nothing in common
+// to 1 for identical strings.
+func Jaro(a, b string) float64 {
+	ra, rb := []rune(a), []rune(b)
+	if len(ra) == 0 && len(rb) == 0 {
+		return 1
+	}
+	if len(ra) == 0 || len(rb) == 0 {
+		return 0
+	}
+
+	// Letters only match if they are no further apart than this.
+	window := max(len(ra), len(rb))/2 - 1
+	window = max(window, 0)
+
+	matchedA := make([]bool, len(ra))
+	matchedB := make([]bool, len(rb))
+	matches := 0
+	for i, r := range ra {
+		for j := max(0, i-window); j < min(len(rb), i+window+1); j++ {
+			if !matchedB[j] && rb[j] == r {
+				matchedA[i], matchedB[j] = true, true
+				matches++
+				break
+			}
+		}
+	}
+	if matches == 0 {
+		return 0
+	}
+
+	// Count matched letters that appear in a different order.
+	transpositions := 0
+	j := 0
+	for i, r := range ra {
+		if !matchedA[i] {
+			continue
+		}
+		for !matchedB[j] {
+			j++
+		}
+		if r != rb[j] {
+			transpositions++
+		}
+		j++
+	}
+
+	m := float64(matches)
+	return (m/float64(len(ra)) + m/float64(len(rb)) + (m-float64(transpositions)/2)/m) / 3
+}
+
+// JaroWinkler returns the Jaro-Winkler similarity of a and b. It is the
+// Jaro similarity boosted for strings that share a prefix of up to four
+// letters, since spelling variants of a name usually start the same way.
+// Only pairs already more than 0.7 similar are boosted.
+func JaroWinkler(a, b string) float64 {
+	jaro := Jaro(a, b)
+	if jaro <= 0.7 {
+		return jaro
+	}
+
+	ra, rb := []rune(a), []rune(b)
+	prefix := 0
+	for prefix < min(len(ra), len(rb), 4) && ra[prefix] == rb[prefix] {
+		prefix++
+	}
+	return jaro + float64(prefix)*0.1*(1-jaro)
+}
@@ -1,24 +1,106 @@
 package main
 
 import (
+	"bufio"
+	"encoding/json"
+	"flag"
 	"fmt"
+	"log"
+	"os"
 	"strings"
+
+	"github.com/TheInvincibleRalph/name-game.git/analysis"
+	"github.com/TheInvincibleRalph/name-game.git/fullname"
 )
 
 func main() {
-	names()
+	if len(os.Args) > 1 {
+		switch os.Args[1] {
+		case "analyze":
+			analyze(os.Args[2:])
+			return
+		case "match":
+			matchNames(os.Args[2:])
+			return
+		case "parse":
+			parseNames(os.Args[2:])
+			return
+		}
+	}
+
+	flags := flag.NewFlagSet("name-game", flag.ExitOnError)
+	asJSON := flags.Bool("json", false, "print the full analysis as JSON")
+	opts := optionFlags(flags)
+	flags.Parse(os.Args[1:])
+
+	options, err := opts()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	names(options, *asJSON)
+}
+
+// optionFlags adds the -lang and -y flags to flags. The returned function
+// builds the analysis options once the flags have been parsed.
+func optionFlags(flags *flag.FlagSet) func() (analysis.Options, error) {
+	lang := flags.String("lang", "en", fmt.Sprintf("language whose vowels to use: %v", analysis.LanguageCodes()))
+	yRule := flags.String("y", "", "when y is a vowel: contextual, always or never (the language's rule if empty)")
+
+	return func() (analysis.Options, error) {
+		language, err := analysis.LookupLanguage(*lang)
+		if err != nil {
+			return analysis.Options{}, err
+		}
+		opts := analysis.Options{Language: language}
+		if *yRule != "" {
+			rule, err := analysis.ParseYRule(*yRule)
+			if err != nil {
+				return analysis.Options{}, err
+			}
+			opts.Y = &rule
+		}
+		return opts, nil
+	}
 }
 
-func names() {
+func names(opts analysis.Options, asJSON bool) {
 	fmt.Println("Enter your name: ")
 
-	var name string
-	fmt.Scanln(&name)
-	for _, v := range strings.ToLower(name) {
-		if v == 'a' || v == 'e' || v == 'i' || v == 'o' || v == 'u' {
-			fmt.Println("Your name contains a vowel.")
-			return
+	//read the whole line so names with several words are kept together
+	name, err := bufio.NewReader(os.Stdin).ReadString('\n')
+	if err != nil && name == "" {
+		log.Fatal("Failed to read your name: ", err)
+	}
+
+	result := analysis.Analyse(name, opts)
+
+	if asJSON {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(result); err != nil {
+			log.Fatal(err)
+		}
+		return
+	}
+
+	printParts(fullname.Parse(name))
+	if result.HasVowel() {
+		fmt.Println("Your name contains a vowel.")
+	} else {
+		fmt.Println("Your name does not contain a vowel.")
+	}
+	fmt.Printf("Vowels (%d):     %s\n", result.Vowels, letters(result, true))
+	fmt.Printf("Consonants (%d): %s\n", result.Consonants, letters(result, false))
+}
+
+// letters lists the vowels or the consonants of a name with their positions
+func letters(result analysis.Result, vowels bool) string {
+	var parts []string
+	for _, l := range result.Letters {
+		if l.Vowel == vowels {
+			parts = append(parts, fmt.Sprintf("%c@%d", l.Rune, l.Position))
 		}
 	}
-	fmt.Println("Your name does not contain a vowel.")
+	return strings.Join(parts, " ")
 }
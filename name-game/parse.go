@@ -0,0 +1,64 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+
+	"github.com/TheInvincibleRalph/name-game.git/fullname"
+)
+
+// parseNames splits names into their parts and prints them as JSON, one
+// object per name. Names come from the arguments or, without any, from
+// stdin one per line.
+func parseNames(args []string) {
+	flags := flag.NewFlagSet("parse", flag.ExitOnError)
+	indent := flags.Bool("indent", false, "indent the JSON output")
+	flags.Parse(args)
+
+	encoder := json.NewEncoder(os.Stdout)
+	if *indent {
+		encoder.SetIndent("", "  ")
+	}
+	emit := func(name string) {
+		if err := encoder.Encode(fullname.Parse(name)); err != nil {
+			log.Fatal(err)
+		}
+	}
+
+	if flags.NArg() > 0 {
+		for _, name := range flags.Args() {
+			emit(name)
+		}
+		return
+	}
+
+	scanner := bufio.NewScanner(os.Stdin)
+	for scanner.Scan() {
+		if name := strings.TrimSpace(scanner.Text()); name != "" {
+			emit(name)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		log.Fatal(err)
+	}
+}
+
+// printParts shows the parts of a name that it has
+func printParts(n fullname.Name) {
+	for _, part := range []struct{ label, value string }{
+		{"Title", n.Title},
+		{"Given name", n.Given},
+		{"Middle names", n.Middle},
+		{"Family name", n.Family},
+		{"Suffix", n.Suffix},
+	} {
+		if part.value != "" {
+			fmt.Printf("%-14s %s\n", part.label+":", part.value)
+		}
+	}
+}
@@ -0,0 +1,527 @@
+package phonetic
+
+import "strings"
+
+// metaphoneLength is the length Double Metaphone codes are cut to.
+const metaphoneLength = 4
+
+// DoubleMetaphone returns the primary and alternate Double Metaphone codes
+// of name, following Lawrence Philips' original rules. The alternate code
+// covers a second likely pronunciation, usually one from another language,
+// so "Smith" gives SM0 and XMT and "Schmidt" gives XMT and SMT. When there
+// is only one pronunciation both codes are the same.
+func DoubleMetaphone(name string) (primary, alternate string) {
+	m := &metaphone{value: Normalize(name)}
+	m.slavoGermanic = strings.ContainsAny(m.value, "WK") ||
+		strings.Contains(m.value, "CZ") || strings.Contains(m.value, "WITZ")
+	m.encode()
+
+	primary, alternate = m.primary.String(), m.alternate.String()
+	return primary[:min(len(primary), metaphoneLength)], alternate[:min(len(alternate), metaphoneLength)]
+}
+
+type metaphone struct {
+	value         string
+	slavoGermanic bool
+
+	primary, alternate strings.Builder
+}
+
+// at returns the letter at i, or 0 outside the name.
+func (m *metaphone) at(i int) byte {
+	if i < 0 || i >= len(m.value) {
+		return 0
+	}
+	return m.value[i]
+}
+
+// is reports whether the length letters at start are one of options.
+func (m *metaphone) is(start, length int, options ...string) bool {
+	if start < 0 || start+length > len(m.value) {
+		return false
+	}
+	sub := m.value[start : start+length]
+	for _, o := range options {
+		if sub == o {
+			return true
+		}
+	}
+	return false
+}
+
+func (m *metaphone) vowel(i int) bool {
+	c := m.at(i)
+	return isVowel(c) || c == 'Y'
+}
+
+func (m *metaphone) add(both string) {
+	m.primary.WriteString(both)
+	m.alternate.WriteString(both)
+}
+
+func (m *metaphone) add2(primary, alternate string) {
+	m.primary.WriteString(primary)
+	m.alternate.WriteString(alternate)
+}
+
+// skip returns the index after i, jumping one further if the next letter is
+// one of doubles.
+func (m *metaphone) skip(i int, doubles ...string) int {
+	if m.is(i+1, 1, doubles...) {
+		return i + 2
+	}
+	return i + 1
+}
+
+func (m *metaphone) encode() {
+	i := 0
+	if m.is(0, 2, "GN", "KN", "PN", "WR", "PS") {
+		i = 1
+	}
+	if m.at(0) == 'X' {
+		// Initial X is pronounced Z, as in "Xavier".
+		m.add("S")
+		i = 1
+	}
+
+	last := len(m.value) - 1
+	for i <= last {
+		switch c := m.at(i); c {
+		case 'A', 'E', 'I', 'O', 'U', 'Y':
+			// Only a vowel at the start of the name is coded.
+			if i == 0 {
+				m.add("A")
+			}
+			i++
+		case 'B':
+			m.add("P")
+			i = m.skip(i, "B")
+		case 'C':
+			i = m.c(i)
+		case 'D':
+			i = m.d(i)
+		case 'F':
+			m.add("F")
+			i = m.skip(i, "F")
+		case 'G':
+			i = m.g(i)
+		case 'H':
+			if (i == 0 || m.vowel(i-1)) && m.vowel(i+1) {
+				m.add("H")
+				i += 2
+			} else {
+				i++
+			}
+		case 'J':
+			i = m.j(i)
+		case 'K':
+			m.add("K")
+			i = m.skip(i, "K")
+		case 'L':
+			i = m.l(i)
+		case 'M':
+			m.add("M")
+			if m.at(i+1) == 'M' || (m.is(i-1, 3, "UMB") && (i+1 == last || m.is(i+2, 2, "ER"))) {
+				// "Dumb", "Thumb"
+				i += 2
+			} else {
+				i++
+			}
+		case 'N':
+			m.add("N")
+			i = m.skip(i, "N")
+		case 'P':
+			if m.at(i+1) == 'H' {
+				m.add("F")
+				i += 2
+			} else {
+				m.add("P")
+				i = m.skip(i, "P", "B")
+			}
+		case 'Q':
+			m.add("K")
+			i = m.skip(i, "Q")
+		case 'R':
+			// French names ending in -ier, as in "Rogier", drop the R in the
+			// primary pronunciation.
+			if i == last && !m.slavoGermanic && m.is(i-2, 2, "IE") && !m.is(i-4, 2, "ME", "MA") {
+				m.add2("", "R")
+			} else {
+				m.add("R")
+			}
+			i = m.skip(i, "R")
+		case 'S':
+			i = m.s(i)
+		case 'T':
+			i = m.t(i)
+		case 'V':
+			m.add("F")
+			i = m.skip(i, "V")
+		case 'W':
+			i = m.w(i)
+		case 'X':
+			// French names ending in -eau or -ou, as in "Breaux", have a
+			// silent X.
+			if !(i == last && (m.is(i-3, 3, "IAU", "EAU") || m.is(i-2, 2, "AU", "OU"))) {
+				m.add("KS")
+			}
+			i = m.skip(i, "C", "X")
+		case 'Z':
+			i = m.z(i)
+		default:
+			i++
+		}
+	}
+}
+
+func (m *metaphone) c(i int) int {
+	switch {
+	case m.germanicCH(i):
+		// Germanic CH as in "Bacher"
+		m.add("K")
+		return i + 2
+	case i == 0 && m.is(i, 6, "CAESAR"):
+		m.add("S")
+		return i + 2
+	case m.is(i, 2, "CH"):
+		return m.ch(i)
+	case m.is(i, 2, "CZ") && !m.is(i-2, 4, "WICZ"):
+		// "Czerny"
+		m.add2("S", "X")
+		return i + 2
+	case m.is(i+1, 3, "CIA"):
+		// "Focaccia"
+		m.add("X")
+		return i + 3
+	case m.is(i, 2, "CC") && !(i == 1 && m.at(0) == 'M'):
+		// Double C but not "McClellan"
+		if m.is(i+2, 1, "I", "E", "H") && !m.is(i+2, 2, "HU") {
+			if (i == 1 && m.at(0) == 'A') || m.is(i-1, 5, "UCCEE", "UCCES") {
+				// "Accident", "Succeed"
+				m.add("KS")
+			} else {
+				// "Bacci", "Bertucci"
+				m.add("X")
+			}
+			return i + 3
+		}
+		m.add("K")
+		return i + 2
+	case m.is(i, 2, "CK", "CG", "CQ"):
+		m.add("K")
+		return i + 2
+	case m.is(i, 2, "CI", "CE", "CY"):
+		// Italian "Cioffi" against English "Cecil"
+		if m.is(i, 3, "CIO", "CIE", "CIA") {
+			m.add2("S", "X")
+		} else {
+			m.add("S")
+		}
+		return i + 2
+	}
+
+	m.add("K")
+	switch {
+	case m.is(i+1, 2, " C", " Q", " G"):
+		// "Mac Caffrey", "Mac Gregor"
+		return i + 3
+	case m.is(i+1, 1, "C", "K", "Q") && !m.is(i+1, 2, "CE", "CI"):
+		return i + 2
+	}
+	return i + 1
+}
+
+// germanicCH reports whether the C at i starts a Germanic CH pronounced K.
+func (m *metaphone) germanicCH(i int) bool {
+	if m.is(i, 4, "CHIA") {
+		return true
+	}
+	if i <= 1 || m.vowel(i-2) || !m.is(i-1, 3, "ACH") {
+		return false
+	}
+	c := m.at(i + 2)
+	return (c != 'I' && c != 'E') || m.is(i-2, 6, "BACHER", "MACHER")
+}
+
+func (m *metaphone) ch(i int) int {
+	switch {
+	case i > 0 && m.is(i, 4, "CHAE"):
+		// "Michael"
+		m.add2("K", "X")
+	case i == 0 && (m.is(i+1, 5, "HARAC", "HARIS") || m.is(i+1, 3, "HOR", "HYM", "HIA", "HEM")) &&
+		!m.is(0, 5, "CHORE"):
+		// Greek roots as in "Chemistry" or "Chorus"
+		m.add("K")
+	case m.is(0, 4, "VAN ", "VON ") || m.is(0, 3, "SCH") ||
+		m.is(i-2, 6, "ORCHES", "ARCHIT", "ORCHID") ||
+		m.is(i+2, 1, "T", "S") ||
+		((m.is(i-1, 1, "A", "O", "U", "E") || i == 0) &&
+			(m.is(i+2, 1, "L", "R", "N", "M", "B", "H", "F", "V", "W", " ") || i+1 == len(m.value)-1)):
+		// Germanic names and words like "Orchestra" or "Wachtler"
+		m.add("K")
+	case i > 0:
+		if m.is(0, 2, "MC") {
+			// "McHugh"
+			m.add("K")
+		} else {
+			m.add2("X", "K")
+		}
+	default:
+		m.add("X")
+	}
+	return i + 2
+}
+
+func (m *metaphone) d(i int) int {
+	switch {
+	case m.is(i, 2, "DG"):
+		if m.is(i+2, 1, "I", "E", "Y") {
+			// "Edge"
+			m.add("J")
+			return i + 3
+		}
+		// "Edgar"
+		m.add("TK")
+		return i + 2
+	case m.is(i, 2, "DT", "DD"):
+		m.add("T")
+		return i + 2
+	}
+	m.add("T")
+	return i + 1
+}
+
+func (m *metaphone) g(i int) int {
+	switch {
+	case m.at(i+1) == 'H':
+		return m.gh(i)
+	case m.at(i+1) == 'N':
+		switch {
+		case i == 1 && m.vowel(0) && !m.slavoGermanic:
+			m.add2("KN", "N")
+		case !m.is(i+2, 2, "EY") && m.at(i+1) != 'Y' && !m.slavoGermanic:
+			// "Cagney"
+			m.add2("N", "KN")
+		default:
+			m.add("KN")
+		}
+		return i + 2
+	case m.is(i+1, 2, "LI") && !m.slavoGermanic:
+		// "Tagliaro"
+		m.add2("KL", "L")
+		return i + 2
+	case i == 0 && (m.at(i+1) == 'Y' ||
+		m.is(i+1, 2, "ES", "EP", "EB", "EL", "EY", "IB", "IL", "IN", "IE", "EI", "ER")):
+		// A hard or soft G at the start, as in "Gerber" or "Gillian"
+		m.add2("K", "J")
+		return i + 2
+	case (m.is(i+1, 2, "ER") || m.at(i+1) == 'Y') &&
+		!m.is(0, 6, "DANGER", "RANGER", "MANGER") &&
+		!m.is(i-1, 1, "E", "I") && !m.is(i-1, 3, "RGY", "OGY"):
+		m.add2("K", "J")
+		return i + 2
+	case m.is(i+1, 1, "E", "I", "Y") || m.is(i-1, 4, "AGGI", "OGGI"):
+		switch {
+		case m.is(0, 4, "VAN ", "VON ") || m.is(0, 3, "SCH") || m.is(i+1, 2, "ET"):
+			// Germanic names
+			m.add("K")
+		case m.is(i+1, 3, "IER"):
+			m.add("J")
+		default:
+			m.add2("J", "K")
+		}
+		return i + 2
+	case m.at(i+1) == 'G':
+		m.add("K")
+		return i + 2
+	}
+	m.add("K")
+	return i + 1
+}
+
+func (m *metaphone) gh(i int) int {
+	switch {
+	case i > 0 && !m.vowel(i-1):
+		m.add("K")
+	case i == 0:
+		// "Ghislane" against "Ghiradelli"
+		if m.at(i+2) == 'I' {
+			m.add("J")
+		} else {
+			m.add("K")
+		}
+	case (i > 1 && m.is(i-2, 1, "B", "H", "D")) ||
+		(i > 2 && m.is(i-3, 1, "B", "H", "D")) ||
+		(i > 3 && m.is(i-4, 1, "B", "H")):
+		// Silent as in "Hugh", "Bough" or "Broughton"
+	case i > 2 && m.at(i-1) == 'U' && m.is(i-3, 1, "C", "G", "L", "R", "T"):
+		// "Laugh", "McLaughlin", "Cough"
+		m.add("F")
+	case i > 0 && m.at(i-1) != 'I':
+		m.add("K")
+	}
+	return i + 2
+}
+
+func (m *metaphone) j(i int) int {
+	if m.is(i, 4, "JOSE") || m.is(0, 4, "SAN ") {
+		// Spanish "Jose" and "San Jacinto"
+		if (i == 0 && m.at(i+4) == ' ') || len(m.value) == 4 || m.is(0, 4, "SAN ") {
+			m.add("H")
+		} else {
+			m.add2("J", "H")
+		}
+		return i + 1
+	}
+
+	switch {
+	case i == 0:
+		// "Jankelowicz"
+		m.add2("J", "A")
+	case m.vowel(i-1) && !m.slavoGermanic && (m.at(i+1) == 'A' || m.at(i+1) == 'O'):
+		// Spanish pronunciation as in "Bajador"
+		m.add2("J", "H")
+	case i == len(m.value)-1:
+		// A final J may be silent, as in "Raj" said the French way.
+		m.add2("J", "")
+	case !m.is(i+1, 1, "L", "T", "K", "S", "N", "M", "B", "Z") && !m.is(i-1, 1, "S", "K", "L"):
+		m.add("J")
+	}
+	return m.skip(i, "J")
+}
+
+func (m *metaphone) l(i int) int {
+	if m.at(i+1) != 'L' {
+		m.add("L")
+		return i + 1
+	}
+
+	// Spanish double L as in "Cabrillo" or "Gallegos"
+	last := len(m.value) - 1
+	if (i == last-2 && m.is(i-1, 4, "ILLO", "ILLA", "ALLE")) ||
+		((m.is(last-1, 2, "AS", "OS") || m.is(last, 1, "A", "O")) && m.is(i-1, 4, "ALLE")) {
+		m.add2("L", "")
+	} else {
+		m.add("L")
+	}
+	return i + 2
+}
+
+func (m *metaphone) s(i int) int {
+	last := len(m.value) - 1
+	switch {
+	case m.is(i-1, 3, "ISL", "YSL"):
+		// Silent as in "Island" or "Carlysle"
+		return i + 1
+	case i == 0 && m.is(i, 5, "SUGAR"):
+		m.add2("X", "S")
+		return i + 1
+	case m.is(i, 2, "SH"):
+		// Germanic "Holmes" and "Rosheim"
+		if m.is(i+1, 4, "HEIM", "HOEK", "HOLM", "HOLZ") {
+			m.add("S")
+		} else {
+			m.add("X")
+		}
+		return i + 2
+	case m.is(i, 3, "SIO", "SIA") || m.is(i, 4, "SIAN"):
+		// Italian and Armenian names
+		if m.slavoGermanic {
+			m.add("S")
+		} else {
+			m.add2("S", "X")
+		}
+		return i + 3
+	case (i == 0 && m.is(i+1, 1, "M", "N", "L", "W")) || m.is(i+1, 1, "Z"):
+		// German and anglicised names as in "Smith" against "Schmidt"
+		m.add2("S", "X")
+		return m.skip(i, "Z")
+	case m.is(i, 2, "SC"):
+		return m.sc(i)
+	case i == last && m.is(i-2, 2, "AI", "OI"):
+		// French as in "Resnais" or "Artois"
+		m.add2("", "S")
+	default:
+		m.add("S")
+	}
+	return m.skip(i, "S", "Z")
+}
+
+func (m *metaphone) sc(i int) int {
+	switch {
+	case m.at(i+2) == 'H':
+		switch {
+		case m.is(i+3, 2, "OO", "ER", "EN", "UY", "ED", "EM"):
+			// Dutch as in "School" or "Schenker"
+			if m.is(i+3, 2, "ER", "EN") {
+				m.add2("X", "SK")
+			} else {
+				m.add("SK")
+			}
+		case i == 0 && !m.vowel(3) && m.at(3) != 'W':
+			m.add2("X", "S")
+		default:
+			m.add("X")
+		}
+	case m.is(i+2, 1, "I", "E", "Y"):
+		m.add("S")
+	default:
+		m.add("SK")
+	}
+	return i + 3
+}
+
+func (m *metaphone) t(i int) int {
+	switch {
+	case m.is(i, 4, "TION"), m.is(i, 3, "TIA", "TCH"):
+		m.add("X")
+		return i + 3
+	case m.is(i, 2, "TH") || m.is(i, 3, "TTH"):
+		// "Thomas" and "Thames" are said with a T
+		if m.is(i+2, 2, "OM", "AM") || m.is(0, 4, "VAN ", "VON ") || m.is(0, 3, "SCH") {
+			m.add("T")
+		} else {
+			m.add2("0", "T")
+		}
+		return i + 2
+	}
+	m.add("T")
+	return m.skip(i, "T", "D")
+}
+
+func (m *metaphone) w(i int) int {
+	switch {
+	case m.is(i, 2, "WR"):
+		m.add("R")
+		return i + 2
+	case i == 0 && (m.vowel(i+1) || m.is(i, 2, "WH")):
+		// "Wasserman" may be said with a V
+		if m.vowel(i + 1) {
+			m.add2("A", "F")
+		} else {
+			m.add("A")
+		}
+	case (i == len(m.value)-1 && m.vowel(i-1)) ||
+		m.is(i-1, 5, "EWSKI", "EWSKY", "OWSKI", "OWSKY") || m.is(0, 3, "SCH"):
+		// Polish as in "Filipowicz"
+		m.add2("", "F")
+	case m.is(i, 4, "WICZ", "WITZ"):
+		m.add2("TS", "FX")
+		return i + 4
+	}
+	return i + 1
+}
+
+func (m *metaphone) z(i int) int {
+	if m.at(i+1) == 'H' {
+		// Chinese pinyin as in "Zhao"
+		m.add("J")
+		return i + 2
+	}
+	if m.is(i+1, 2, "ZO", "ZI", "ZA") || (m.slavoGermanic && i > 0 && m.at(i-1) != 'T') {
+		m.add2("S", "TS")
+	} else {
+		m.add("S")
+	}
+	return m.skip(i, "Z")
+}
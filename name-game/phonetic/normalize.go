@@ -0,0 +1,74 @@
+// Package phonetic encodes names by how they sound, so that variant
+// spellings of the same name get the same code. It implements American
+// Soundex, Double Metaphone and NYSIIS.
+//
+// The encodings are defined over the letters A to Z. Names are normalized
+// first: accents are stripped, a few letters that do not decompose are
+// spelled out (ß as SS, æ as AE, ø as O) and anything else is dropped.
+package phonetic
+
+import (
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// spelled are letters that have no decomposition but an obvious ASCII
+// spelling.
+var spelled = map[rune]string{
+	'ß': "SS",
+	'æ': "AE", 'Æ': "AE",
+	'œ': "OE", 'Œ': "OE",
+	'ø': "O", 'Ø': "O",
+	'ł': "L", 'Ł': "L",
+	'đ': "D", 'Đ': "D",
+	'ð': "D", 'Ð': "D",
+	'þ': "TH", 'Þ': "TH",
+	'ı': "I",
+}
+
+// Normalize upper cases name and reduces it to the letters A to Z, with
+// words separated by single spaces. Hyphens separate words; apostrophes
+// and other punctuation are dropped, so "O'Neil-Smith" becomes
+// "ONEIL SMITH".
+func Normalize(name string) string {
+	var b strings.Builder
+	b.Grow(len(name))
+
+	space := false
+	for _, r := range norm.NFD.String(name) {
+		switch {
+		case r >= 'a' && r <= 'z':
+			r -= 'a' - 'A'
+		case r >= 'A' && r <= 'Z':
+		case unicode.IsSpace(r) || r == '-':
+			space = b.Len() > 0
+			continue
+		case spelled[r] != "":
+		default:
+			continue
+		}
+
+		if space {
+			b.WriteByte(' ')
+			space = false
+		}
+		if s, ok := spelled[r]; ok {
+			b.WriteString(s)
+		} else {
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+// letters is Normalize without the spaces, for encodings that treat the
+// whole name as one word.
+func letters(name string) string {
+	return strings.ReplaceAll(Normalize(name), " ", "")
+}
+
+func isVowel(c byte) bool {
+	return c == 'A' || c == 'E' || c == 'I' || c == 'O' || c == 'U'
+}
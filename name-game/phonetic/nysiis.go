@@ -0,0 +1,96 @@
+package phonetic
+
+import "strings"
+
+// NYSIIS returns the New York State Identification and Intelligence System
+// code of name, as in "Brian", "Brown" and "Brun" all giving BRAN. The full
+// code is returned; the original system kept only the first six letters.
+func NYSIIS(name string) string {
+	s := letters(name)
+	if s == "" {
+		return ""
+	}
+
+	// Translate the first letters of the name.
+	for _, r := range [...]struct{ from, to string }{
+		{"MAC", "MCC"}, {"KN", "NN"}, {"K", "C"}, {"PH", "FF"}, {"PF", "FF"}, {"SCH", "SSS"},
+	} {
+		if strings.HasPrefix(s, r.from) {
+			s = r.to + s[len(r.from):]
+			break
+		}
+	}
+
+	// And the last ones.
+	switch {
+	case strings.HasSuffix(s, "EE"), strings.HasSuffix(s, "IE"):
+		s = s[:len(s)-2] + "Y"
+	case strings.HasSuffix(s, "DT"), strings.HasSuffix(s, "RT"), strings.HasSuffix(s, "RD"),
+		strings.HasSuffix(s, "NT"), strings.HasSuffix(s, "ND"):
+		s = s[:len(s)-2] + "D"
+	}
+
+	// Every letter after the first is translated in place, so later rules
+	// see the letters before them as already translated.
+	chars := []byte(s)
+	key := []byte{chars[0]}
+	at := func(i int) byte {
+		if i < len(chars) {
+			return chars[i]
+		}
+		return ' '
+	}
+
+	for i := 1; i < len(chars); i++ {
+		prev, c, next := chars[i-1], chars[i], at(i+1)
+
+		var to string
+		switch {
+		case c == 'E' && next == 'V':
+			to = "AF"
+		case isVowel(c):
+			to = "A"
+		case c == 'Q':
+			to = "G"
+		case c == 'Z':
+			to = "S"
+		case c == 'M':
+			to = "N"
+		case c == 'K' && next == 'N':
+			to = "NN"
+		case c == 'K':
+			to = "C"
+		case c == 'S' && next == 'C' && at(i+2) == 'H':
+			to = "SSS"
+		case c == 'P' && next == 'H':
+			to = "FF"
+		case c == 'H' && (!isVowel(prev) || !isVowel(next)):
+			to = string(prev)
+		case c == 'W' && isVowel(prev):
+			to = string(prev)
+		default:
+			to = string(c)
+		}
+		copy(chars[i:], to)
+
+		if chars[i] != chars[i-1] {
+			key = append(key, chars[i])
+		}
+	}
+
+	if len(key) > 1 {
+		last := key[len(key)-1]
+		if last == 'S' {
+			key = key[:len(key)-1]
+			last = key[len(key)-1]
+		}
+		if len(key) > 2 && key[len(key)-2] == 'A' && last == 'Y' {
+			key = append(key[:len(key)-2], 'Y')
+		}
+		// The first letter is always kept, as in "Az" giving A.
+		if last == 'A' && len(key) > 1 {
+			key = key[:len(key)-1]
+		}
+	}
+	return string(key)
+}
@@ -0,0 +1,244 @@
+package phonetic
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestNormalize(t *testing.T) {
+	tests := []struct {
+		name, want string
+	}{
+		{"O'Neil-Smith", "ONEIL SMITH"},
+		{"  José   María ", "JOSE MARIA"},
+		{"Straße", "STRASSE"},
+		{"Bjørn Ærø", "BJORN AERO"},
+		{"Łukasz Þór", "LUKASZ THOR"},
+		{"李 Li", "LI"},
+		{"", ""},
+	}
+	for _, tt := range tests {
+		if got := Normalize(tt.name); got != tt.want {
+			t.Errorf("Normalize(%q) = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+// The Soundex vectors are the examples given with the American Soundex
+// rules by the US National Archives, and those of Knuth's Art of Computer
+// Programming.
+func TestSoundex(t *testing.T) {
+	tests := []struct {
+		name, want string
+	}{
+		{"Robert", "R163"},
+		{"Rupert", "R163"},
+		{"Rubin", "R150"},
+		{"Ashcraft", "A261"},
+		{"Ashcroft", "A261"},
+		{"Tymczak", "T522"},
+		{"Pfister", "P236"},
+		{"Honeyman", "H555"},
+		{"Gutierrez", "G362"},
+		{"Jackson", "J250"},
+		{"Washington", "W252"},
+		{"Lee", "L000"},
+		{"Lloyd", "L300"},
+		{"Burroughs", "B620"},
+		{"Burrows", "B620"},
+		{"O'Hara", "O600"},
+		{"tymczak", "T522"},
+		{"Ümit", "U530"},
+		{"", ""},
+		{"1234", ""},
+	}
+	for _, tt := range tests {
+		if got := Soundex(tt.name); got != tt.want {
+			t.Errorf("Soundex(%q) = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+// The NYSIIS vectors are those of the original New York State report, as
+// also used by Apache Commons Codec, with the codes left untruncated.
+func TestNYSIIS(t *testing.T) {
+	tests := []struct {
+		name, want string
+	}{
+		{"Brian", "BRAN"},
+		{"Brown", "BRAN"},
+		{"Brun", "BRAN"},
+		{"Capp", "CAP"},
+		{"Cope", "CAP"},
+		{"Copp", "CAP"},
+		{"Kipp", "CAP"},
+		{"Dane", "DAN"},
+		{"Dean", "DAN"},
+		{"Dionne", "DAN"},
+		{"Smith", "SNAT"},
+		{"Schmit", "SNAT"},
+		{"Schmidt", "SNAD"},
+		{"Knight", "NAGT"},
+		{"Mitchell", "MATCAL"},
+		{"MacIntosh", "MCANT"},
+		{"Mackenzie", "MCANSY"},
+		{"Phillipson", "FALAPSAN"},
+		{"Kessler", "CASLAR"},
+		{"Kraft", "CRAFT"},
+		{"Wheeler", "WALAR"},
+		{"Watkins", "WATCAN"},
+		{"Lawrence", "LARANC"},
+		{"Louis", "L"},
+		{"Az", "A"},
+		{"", ""},
+	}
+	for _, tt := range tests {
+		if got := NYSIIS(tt.name); got != tt.want {
+			t.Errorf("NYSIIS(%q) = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+// The Double Metaphone vectors are taken from the examples in Lawrence
+// Philips' original implementation, one or more for each special case.
+func TestDoubleMetaphone(t *testing.T) {
+	tests := []struct {
+		name, primary, alternate string
+	}{
+		{"Smith", "SM0", "XMT"},
+		{"Schmidt", "XMT", "SMT"},
+		{"Jose", "HS", "HS"},
+		{"Jones", "JNS", "ANS"},
+		{"Caesar", "SSR", "SSR"},
+		{"Xavier", "SF", "SFR"},
+		{"Rogier", "RJ", "RJR"},
+		{"Arnow", "ARN", "ARNF"},
+		{"Arnoff", "ARNF", "ARNF"},
+		{"Filipowicz", "FLPT", "FLPF"},
+		{"Jankelowicz", "JNKL", "ANKL"},
+		{"Womo", "AM", "FM"},
+		{"Wasserman", "ASRM", "FSRM"},
+		{"Gallegos", "KLKS", "KKS"},
+		{"Cabrillo", "KPRL", "KPR"},
+		{"Tagliaro", "TKLR", "TLR"},
+		{"Tichner", "TXNR", "TKNR"},
+		{"Thumbail", "0MPL", "TMPL"},
+		{"Zhao", "J", "J"},
+		{"McHugh", "MK", "MK"},
+		{"Dumb", "TM", "TM"},
+		{"Campbell", "KMPL", "KMPL"},
+		{"Knight", "NT", "NT"},
+		{"Gnome", "NM", "NM"},
+		{"Gough", "KF", "KF"},
+		{"Laugh", "LF", "LF"},
+		{"Hugh", "H", "H"},
+		{"Ghislane", "JLN", "JLN"},
+		{"Ghiradelli", "JRTL", "JRTL"},
+		{"Bacchus", "PKS", "PKS"},
+		{"Bellocchio", "PLX", "PLX"},
+		{"Accident", "AKST", "AKST"},
+		{"Edge", "AJ", "AJ"},
+		{"Edgar", "ATKR", "ATKR"},
+		{"Chianti", "KNT", "KNT"},
+		{"Michael", "MKL", "MXL"},
+		{"Chorus", "KRS", "KRS"},
+		{"Orchestra", "ARKS", "ARKS"},
+		{"Charles", "XRLS", "XRLS"},
+		{"Sugar", "XKR", "SKR"},
+		{"Island", "ALNT", "ALNT"},
+		{"Carlisle", "KRLL", "KRLL"},
+		{"Raj", "RJ", "R"},
+		{"", "", ""},
+	}
+	for _, tt := range tests {
+		primary, alternate := DoubleMetaphone(tt.name)
+		if primary != tt.primary || alternate != tt.alternate {
+			t.Errorf("DoubleMetaphone(%q) = %q, %q, want %q, %q", tt.name, primary, alternate, tt.primary, tt.alternate)
+		}
+	}
+}
+
+const upper = "ABCDEFGHIJKLMNOPQRSTUVWXYZ"
+
+// only reports whether every byte of code is one of allowed.
+func only(code, allowed string) bool {
+	for i := 0; i < len(code); i++ {
+		if strings.IndexByte(allowed, code[i]) < 0 {
+			return false
+		}
+	}
+	return true
+}
+
+func FuzzSoundex(f *testing.F) {
+	for _, name := range []string{"Robert", "Ashcraft", "O'Hara", "Bjørn", ""} {
+		f.Add(name)
+	}
+	f.Fuzz(func(t *testing.T, name string) {
+		code := Soundex(name)
+		if code == "" {
+			if letters(name) != "" {
+				t.Errorf("Soundex(%q) is empty for a name with letters", name)
+			}
+			return
+		}
+		if len(code) != 4 || !only(code[:1], upper) || !only(code[1:], "0123456") {
+			t.Errorf("Soundex(%q) = %q, want a letter and three digits", name, code)
+		}
+		if again := Soundex(Normalize(name)); again != code {
+			t.Errorf("Soundex of the normalized %q = %q, want %q", name, again, code)
+		}
+	})
+}
+
+func FuzzNYSIIS(f *testing.F) {
+	for _, name := range []string{"MacIntosh", "Schmidt", "Knight", "Eve", "Hayes", ""} {
+		f.Add(name)
+	}
+	f.Fuzz(func(t *testing.T, name string) {
+		code := NYSIIS(name)
+		if !only(code, upper) || len(code) > len(letters(name))+1 {
+			t.Errorf("NYSIIS(%q) = %q", name, code)
+		}
+		if (code == "") != (letters(name) == "") {
+			t.Errorf("NYSIIS(%q) = %q, want a code exactly when the name has letters", name, code)
+		}
+	})
+}
+
+func FuzzDoubleMetaphone(f *testing.F) {
+	for _, name := range []string{"Schmidt", "Jankelowicz", "Ghislane", "Womo", "Caesar", ""} {
+		f.Add(name)
+	}
+	f.Fuzz(func(t *testing.T, name string) {
+		primary, alternate := DoubleMetaphone(name)
+		for _, code := range []string{primary, alternate} {
+			if len(code) > 4 || !only(code, upper+"0") {
+				t.Errorf("DoubleMetaphone(%q) = %q, %q", name, primary, alternate)
+			}
+		}
+	})
+}
+
+var benchmarkNames = []string{
+	"Robert", "Ashcraft", "Tymczak", "Jankelowicz", "MacIntosh", "Schmidt",
+	"Gallegos", "Bjørn Øster", "María José Carreño Quiñones", "O'Neil-Smith",
+}
+
+func BenchmarkSoundex(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		Soundex(benchmarkNames[i%len(benchmarkNames)])
+	}
+}
+
+func BenchmarkNYSIIS(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		NYSIIS(benchmarkNames[i%len(benchmarkNames)])
+	}
+}
+
+func BenchmarkDoubleMetaphone(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		DoubleMetaphone(benchmarkNames[i%len(benchmarkNames)])
+	}
+}
@@ -0,0 +1,41 @@
+package phonetic
+
+// soundexCodes maps each letter to its Soundex digit. Vowels and Y are 0;
+// H and W are handled separately since they do not separate letters with
+// the same code.
+var soundexCodes = [26]byte{
+	//A  B    C    D    E  F    G    H  I  J    K    L    M    N    O  P    Q    R    S    T    U  V    W  X    Y  Z
+	0, '1', '2', '3', 0, '1', '2', 0, 0, '2', '2', '4', '5', '5', 0, '1', '2', '6', '2', '3', 0, '1', 0, '2', 0, '2',
+}
+
+// Soundex returns the American Soundex code of name: its first letter
+// followed by three digits, as in "Robert" and "Rupert" both giving R163.
+// It returns an empty string if name has no letters.
+func Soundex(name string) string {
+	s := letters(name)
+	if s == "" {
+		return ""
+	}
+
+	code := []byte{s[0]}
+	last := soundexCodes[s[0]-'A']
+	for i := 1; i < len(s) && len(code) < 4; i++ {
+		c := s[i]
+		digit := soundexCodes[c-'A']
+		switch {
+		case c == 'H' || c == 'W':
+			// Letters with the same code either side of H or W are coded
+			// once, as in "Ashcraft" giving A261.
+		case digit == 0:
+			last = 0
+		case digit != last:
+			code = append(code, digit)
+			last = digit
+		}
+	}
+
+	for len(code) < 4 {
+		code = append(code, '0')
+	}
+	return string(code)
+}
@@ -0,0 +1,135 @@
+package textutil
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// Words splits s into words for case conversion. Words are separated by
+// anything that is not a letter or digit and by changes of case, so
+// "parseHTTPRequest", "parse_http_request" and "Parse HTTP request" all give
+// parse, HTTP and request.
+func Words(s string) []string {
+	var words []string
+	runes := []rune(s)
+	start := -1
+
+	for i, r := range runes {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+			if start >= 0 {
+				words = append(words, string(runes[start:i]))
+				start = -1
+			}
+			continue
+		}
+		if start < 0 {
+			start = i
+			continue
+		}
+
+		prev := runes[i-1]
+		lowerToUpper := unicode.IsUpper(r) && !unicode.IsUpper(prev)
+		// The last capital of an acronym starts the next word, as the R in
+		// "HTTPRequest".
+		acronymEnd := unicode.IsUpper(prev) && unicode.IsUpper(r) &&
+			i+1 < len(runes) && unicode.IsLower(runes[i+1])
+		if lowerToUpper || acronymEnd {
+			words = append(words, string(runes[start:i]))
+			start = i
+		}
+	}
+	if start >= 0 {
+		words = append(words, string(runes[start:]))
+	}
+	return words
+}
+
+// SnakeCase converts s to snake_case.
+func SnakeCase(s string) string {
+	return joinLower(Words(s), '_')
+}
+
+// KebabCase converts s to kebab-case.
+func KebabCase(s string) string {
+	return joinLower(Words(s), '-')
+}
+
+// CamelCase converts s to camelCase.
+func CamelCase(s string) string {
+	return joinTitled(Words(s), false)
+}
+
+// PascalCase converts s to PascalCase.
+func PascalCase(s string) string {
+	return joinTitled(Words(s), true)
+}
+
+func joinLower(words []string, sep byte) string {
+	var b strings.Builder
+	b.Grow(wordsLen(words) + len(words))
+	for i, w := range words {
+		if i > 0 {
+			b.WriteByte(sep)
+		}
+		b.WriteString(strings.ToLower(w))
+	}
+	return b.String()
+}
+
+// joinTitled joins words with the first letter of each upper cased, except
+// for the first word unless upperFirst is set.
+func joinTitled(words []string, upperFirst bool) string {
+	var b strings.Builder
+	b.Grow(wordsLen(words))
+	for i, w := range words {
+		w = strings.ToLower(w)
+		if i == 0 && !upperFirst {
+			b.WriteString(w)
+			continue
+		}
+		r, size := utf8.DecodeRuneInString(w)
+		b.WriteRune(unicode.ToTitle(r))
+		b.WriteString(w[size:])
+	}
+	return b.String()
+}
+
+func wordsLen(words []string) int {
+	n := 0
+	for _, w := range words {
+		n += len(w)
+	}
+	return n
+}
+
+// Slugify turns s into a lower case, URL safe slug of ASCII letters, digits
+// and hyphens, so "Crème Brûlée: A Recipe!" becomes "creme-brulee-a-recipe".
+// Accents are stripped; other characters outside ASCII are dropped.
+func Slugify(s string) string {
+	var b strings.Builder
+	b.Grow(len(s))
+
+	hyphen := false
+	for _, r := range norm.NFD.String(s) {
+		switch {
+		case r >= 'a' && r <= 'z', r >= '0' && r <= '9':
+		case r >= 'A' && r <= 'Z':
+			r += 'a' - 'A'
+		case unicode.Is(unicode.Mn, r):
+			continue
+		default:
+			hyphen = b.Len() > 0
+			continue
+		}
+
+		if hyphen {
+			b.WriteByte('-')
+			hyphen = false
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
@@ -0,0 +1,278 @@
+package textutil
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"unicode/utf8"
+)
+
+// ErrOutOfRange is returned for lines, columns and offsets past the end of
+// the input.
+var ErrOutOfRange = errors.New("position out of range")
+
+// indexChunk is how much of the input is read at a time while indexing.
+const indexChunk = 64 * 1024
+
+// Position is a place in the input as a line and a column, both counted
+// from 1. Columns count runes, not bytes.
+type Position struct {
+	Line   int
+	Column int
+}
+
+func (p Position) String() string {
+	return fmt.Sprintf("%d:%d", p.Line, p.Column)
+}
+
+// LineReader reads an io.ReadSeeker by line. It indexes where each line
+// starts as it goes, and only reads as far into the input as it needs to,
+// so the start of a large file can be used without scanning the rest.
+//
+// A LineReader is itself an io.ReadSeeker: SeekLine moves the underlying
+// reader to the start of a line and Read carries on from there. Line,
+// Lines, Position and Offset leave the read position where it was.
+//
+// Lines end with "\n". A "\r" before it is dropped from the text Line and
+// Lines return, though it still has a column for Position and Offset. A
+// final newline ends the last line rather than starting an empty one.
+type LineReader struct {
+	rs io.ReadSeeker
+
+	// starts holds the byte offset of each line found so far, so line n
+	// starts at starts[n-1].
+	starts  []int64
+	indexed int64
+	done    bool
+}
+
+// NewLineReader returns a LineReader over rs. Nothing is read until a line
+// is asked for.
+func NewLineReader(rs io.ReadSeeker) *LineReader {
+	return &LineReader{rs: rs, starts: []int64{0}}
+}
+
+// Read reads from the current position of the underlying reader.
+func (l *LineReader) Read(p []byte) (int, error) {
+	return l.rs.Read(p)
+}
+
+// Seek moves the underlying reader to a byte offset.
+func (l *LineReader) Seek(offset int64, whence int) (int64, error) {
+	return l.rs.Seek(offset, whence)
+}
+
+// SeekLine moves the read position to the start of line n and returns its
+// byte offset.
+func (l *LineReader) SeekLine(n int) (int64, error) {
+	start, _, err := l.bounds(n)
+	if err != nil {
+		return 0, err
+	}
+	return l.rs.Seek(start, io.SeekStart)
+}
+
+// LineCount returns the number of lines in the input. It has to index the
+// whole input to find out.
+func (l *LineReader) LineCount() (int, error) {
+	if err := l.index(func() bool { return false }); err != nil {
+		return 0, err
+	}
+	return l.count(), nil
+}
+
+// Line returns line n without its line ending.
+func (l *LineReader) Line(n int) (string, error) {
+	lines, err := l.Lines(n, n)
+	if err != nil {
+		return "", err
+	}
+	return lines[0], nil
+}
+
+// Lines returns lines from to to, inclusive, without their line endings.
+func (l *LineReader) Lines(from, to int) ([]string, error) {
+	if from > to {
+		return nil, fmt.Errorf("%w: lines %d to %d", ErrOutOfRange, from, to)
+	}
+	start, _, err := l.bounds(from)
+	if err != nil {
+		return nil, err
+	}
+	_, end, err := l.bounds(to)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := l.readRange(start, end)
+	if err != nil {
+		return nil, err
+	}
+
+	lines := strings.SplitAfter(string(data), "\n")
+	if lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+	for i, line := range lines {
+		lines[i] = strings.TrimSuffix(strings.TrimSuffix(line, "\n"), "\r")
+	}
+	return lines, nil
+}
+
+// Position translates a byte offset into a line and column. An offset in
+// the middle of a multi-byte character gives the column of that character.
+// The end of an input that finishes with a newline is column 1 of the line
+// after the last one.
+func (l *LineReader) Position(offset int64) (Position, error) {
+	if offset < 0 {
+		return Position{}, fmt.Errorf("%w: offset %d", ErrOutOfRange, offset)
+	}
+	if err := l.index(func() bool { return l.indexed > offset }); err != nil {
+		return Position{}, err
+	}
+	if l.done && offset > l.indexed {
+		return Position{}, fmt.Errorf("%w: offset %d is past the end at %d", ErrOutOfRange, offset, l.indexed)
+	}
+
+	// The line is the last one starting at or before offset.
+	line := sort.Search(len(l.starts), func(i int) bool { return l.starts[i] > offset })
+	start := l.starts[line-1]
+
+	data, err := l.readRange(start, offset)
+	if err != nil {
+		return Position{}, err
+	}
+	// An offset in the middle of a character belongs to that character, so
+	// its first bytes are left out of the count.
+	last := len(data) - 1
+	for last > 0 && len(data)-last < utf8.UTFMax && !utf8.RuneStart(data[last]) {
+		last--
+	}
+	if last >= 0 && !utf8.FullRune(data[last:]) {
+		data = data[:last]
+	}
+	column := utf8.RuneCount(data) + 1
+	return Position{Line: line, Column: column}, nil
+}
+
+// Offset translates a line and column into a byte offset. The column just
+// past the end of a line is allowed and gives the offset of its line ending.
+func (l *LineReader) Offset(pos Position) (int64, error) {
+	// The end of an input that finishes with a newline is only known once
+	// the input has been indexed that far.
+	if err := l.index(func() bool { return len(l.starts) > pos.Line }); err != nil {
+		return 0, err
+	}
+	if l.done && pos == (Position{Line: len(l.starts), Column: 1}) && l.starts[pos.Line-1] == l.indexed {
+		return l.indexed, nil
+	}
+
+	start, end, err := l.bounds(pos.Line)
+	if err != nil {
+		return 0, err
+	}
+	data, err := l.readRange(start, end)
+	if err != nil {
+		return 0, err
+	}
+
+	// Columns are counted on the line as stored, so a "\r" before the
+	// newline has a column of its own.
+	line := strings.TrimSuffix(string(data), "\n")
+	if pos.Column < 1 || pos.Column > utf8.RuneCountInString(line)+1 {
+		return 0, fmt.Errorf("%w: column %d of line %d", ErrOutOfRange, pos.Column, pos.Line)
+	}
+	return start + int64(runeOffset(line, pos.Column-1)), nil
+}
+
+// bounds returns the byte range of line n, including its line ending.
+func (l *LineReader) bounds(n int) (start, end int64, err error) {
+	if n < 1 {
+		return 0, 0, fmt.Errorf("%w: line %d", ErrOutOfRange, n)
+	}
+	// The end of line n is the start of line n+1, or the end of the input.
+	if err := l.index(func() bool { return len(l.starts) > n }); err != nil {
+		return 0, 0, err
+	}
+	if n > l.count() {
+		return 0, 0, fmt.Errorf("%w: line %d of %d", ErrOutOfRange, n, l.count())
+	}
+
+	start = l.starts[n-1]
+	if n < len(l.starts) {
+		return start, l.starts[n], nil
+	}
+	return start, l.indexed, nil
+}
+
+// count returns the number of lines indexed so far.
+func (l *LineReader) count() int {
+	n := len(l.starts)
+	if l.done && l.starts[n-1] == l.indexed {
+		// Nothing follows the last newline, so no line starts there.
+		n--
+	}
+	return n
+}
+
+// index reads on through the input, recording where lines start, until
+// enough returns true or the input ends.
+func (l *LineReader) index(enough func() bool) error {
+	if l.done || enough() {
+		return nil
+	}
+
+	return l.keepPosition(func() error {
+		if _, err := l.rs.Seek(l.indexed, io.SeekStart); err != nil {
+			return err
+		}
+
+		buf := make([]byte, indexChunk)
+		for !enough() {
+			n, err := l.rs.Read(buf)
+			for i, b := range buf[:n] {
+				if b == '\n' {
+					l.starts = append(l.starts, l.indexed+int64(i)+1)
+				}
+			}
+			l.indexed += int64(n)
+
+			if errors.Is(err, io.EOF) {
+				l.done = true
+				return nil
+			}
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// readRange reads the bytes from start up to end.
+func (l *LineReader) readRange(start, end int64) ([]byte, error) {
+	data := make([]byte, end-start)
+	err := l.keepPosition(func() error {
+		if _, err := l.rs.Seek(start, io.SeekStart); err != nil {
+			return err
+		}
+		_, err := io.ReadFull(l.rs, data)
+		return err
+	})
+	return data, err
+}
+
+// keepPosition runs fn and then puts the read position back where it was.
+func (l *LineReader) keepPosition(fn func() error) error {
+	pos, err := l.rs.Seek(0, io.SeekCurrent)
+	if err != nil {
+		return err
+	}
+	if err := fn(); err != nil {
+		return err
+	}
+	_, err = l.rs.Seek(pos, io.SeekStart)
+	return err
+}
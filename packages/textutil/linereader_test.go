@@ -0,0 +1,197 @@
+package textutil
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+	"unicode/utf8"
+)
+
+func TestLineReaderLines(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  []string
+	}{
+		{"empty", "", nil},
+		{"one line", "one", []string{"one"}},
+		{"trailing newline", "one\ntwo\n", []string{"one", "two"}},
+		{"no trailing newline", "one\ntwo", []string{"one", "two"}},
+		{"blank lines", "\n\none\n\n", []string{"", "", "one", ""}},
+		{"crlf", "one\r\ntwo\r\n", []string{"one", "two"}},
+		{"mixed endings", "one\r\ntwo\nthree\r", []string{"one", "two", "three"}},
+		{"lone cr", "one\rtwo\n", []string{"one\rtwo"}},
+		{"multibyte", "héllo\n世界\n🙂", []string{"héllo", "世界", "🙂"}},
+	}
+	for _, tt := range tests {
+		l := NewLineReader(strings.NewReader(tt.input))
+		n, err := l.LineCount()
+		if err != nil || n != len(tt.want) {
+			t.Errorf("%s: LineCount() = %d, %v, want %d", tt.name, n, err, len(tt.want))
+			continue
+		}
+		for i, want := range tt.want {
+			if got, err := l.Line(i + 1); err != nil || got != want {
+				t.Errorf("%s: Line(%d) = %q, %v, want %q", tt.name, i+1, got, err, want)
+			}
+		}
+		if len(tt.want) > 0 {
+			got, err := l.Lines(1, len(tt.want))
+			if err != nil || fmt.Sprintf("%q", got) != fmt.Sprintf("%q", tt.want) {
+				t.Errorf("%s: Lines(1, %d) = %q, %v, want %q", tt.name, len(tt.want), got, err, tt.want)
+			}
+		}
+		if _, err := l.Line(len(tt.want) + 1); !errors.Is(err, ErrOutOfRange) {
+			t.Errorf("%s: Line(%d) = %v, want ErrOutOfRange", tt.name, len(tt.want)+1, err)
+		}
+	}
+}
+
+func TestLineReaderPosition(t *testing.T) {
+	tests := []struct {
+		input  string
+		offset int64
+		want   Position
+	}{
+		{"", 0, Position{1, 1}},
+		{"one\ntwo", 0, Position{1, 1}},
+		{"one\ntwo", 3, Position{1, 4}},
+		{"one\ntwo", 4, Position{2, 1}},
+		{"one\ntwo", 7, Position{2, 4}},
+		// The end of input after a final newline starts the next line.
+		{"one\n", 4, Position{2, 1}},
+		// "\r" has a column of its own.
+		{"one\r\ntwo", 3, Position{1, 4}},
+		{"one\r\ntwo", 4, Position{1, 5}},
+		{"one\r\ntwo", 5, Position{2, 1}},
+		// Columns count runes, and an offset inside a rune is that rune.
+		{"héllo", 1, Position{1, 2}},
+		{"héllo", 2, Position{1, 2}},
+		{"héllo", 3, Position{1, 3}},
+		{"a\n世界", 5, Position{2, 2}},
+		{"a\n世界", 7, Position{2, 2}},
+		{"a\n世界", 8, Position{2, 3}},
+		{"🙂x", 3, Position{1, 1}},
+		{"🙂x", 4, Position{1, 2}},
+	}
+	for _, tt := range tests {
+		l := NewLineReader(strings.NewReader(tt.input))
+		if got, err := l.Position(tt.offset); err != nil || got != tt.want {
+			t.Errorf("Position(%d) in %q = %v, %v, want %v", tt.offset, tt.input, got, err, tt.want)
+		}
+	}
+
+	for _, offset := range []int64{-1, 8} {
+		l := NewLineReader(strings.NewReader("one\ntwo"))
+		if _, err := l.Position(offset); !errors.Is(err, ErrOutOfRange) {
+			t.Errorf("Position(%d) = %v, want ErrOutOfRange", offset, err)
+		}
+	}
+}
+
+func TestLineReaderOffset(t *testing.T) {
+	tests := []struct {
+		input string
+		pos   Position
+		want  int64
+	}{
+		{"", Position{1, 1}, 0},
+		{"one\ntwo", Position{1, 1}, 0},
+		{"one\ntwo", Position{1, 4}, 3},
+		{"one\ntwo", Position{2, 4}, 7},
+		{"one\n", Position{2, 1}, 4},
+		{"one\r\ntwo", Position{1, 5}, 4},
+		{"one\r\ntwo", Position{2, 2}, 6},
+		{"héllo", Position{1, 3}, 3},
+		{"a\n世界", Position{2, 3}, 8},
+	}
+	for _, tt := range tests {
+		l := NewLineReader(strings.NewReader(tt.input))
+		if got, err := l.Offset(tt.pos); err != nil || got != tt.want {
+			t.Errorf("Offset(%v) in %q = %d, %v, want %d", tt.pos, tt.input, got, err, tt.want)
+		}
+	}
+
+	bad := []Position{{0, 1}, {1, 0}, {1, 5}, {3, 1}, {2, 5}}
+	for _, pos := range bad {
+		l := NewLineReader(strings.NewReader("one\ntwo"))
+		if _, err := l.Offset(pos); !errors.Is(err, ErrOutOfRange) {
+			t.Errorf("Offset(%v) = %v, want ErrOutOfRange", pos, err)
+		}
+	}
+}
+
+func TestLineReaderRoundTrip(t *testing.T) {
+	inputs := []string{
+		"",
+		"one\ntwo",
+		"one\ntwo\n",
+		"one\r\n\r\ntwo\r\n",
+		"héllo\n世界\n🙂 and more\n\n",
+	}
+	for _, input := range inputs {
+		l := NewLineReader(strings.NewReader(input))
+		for offset := 0; offset <= len(input); offset++ {
+			if offset < len(input) && !utf8.RuneStart(input[offset]) {
+				continue
+			}
+			pos, err := l.Position(int64(offset))
+			if err != nil {
+				t.Fatalf("Position(%d) in %q: %v", offset, input, err)
+			}
+			back, err := l.Offset(pos)
+			if err != nil || back != int64(offset) {
+				t.Errorf("in %q offset %d is %v, which is offset %d, %v", input, offset, pos, back, err)
+			}
+		}
+	}
+}
+
+func TestLineReaderSeekLine(t *testing.T) {
+	l := NewLineReader(strings.NewReader("one\ntwo\nthree\n"))
+	if _, err := l.Line(3); err != nil {
+		t.Fatal(err)
+	}
+	offset, err := l.SeekLine(2)
+	if err != nil || offset != 4 {
+		t.Fatalf("SeekLine(2) = %d, %v, want 4", offset, err)
+	}
+	rest, err := io.ReadAll(l)
+	if err != nil || string(rest) != "two\nthree\n" {
+		t.Errorf("reading after SeekLine(2) gave %q, %v", rest, err)
+	}
+
+	// Looking lines up leaves the read position alone.
+	l.SeekLine(3)
+	l.Line(1)
+	l.Position(0)
+	if rest, _ := io.ReadAll(l); string(rest) != "three\n" {
+		t.Errorf("reading after SeekLine(3) and lookups gave %q", rest)
+	}
+}
+
+func TestLineReaderLargeInput(t *testing.T) {
+	// Lines cross the chunks the input is indexed in.
+	var b strings.Builder
+	for i := 1; i <= 20000; i++ {
+		fmt.Fprintf(&b, "line %d: 世界\n", i)
+	}
+	input := b.String()
+	if len(input) <= 2*indexChunk {
+		t.Fatalf("input of %d bytes fits in two chunks", len(input))
+	}
+
+	l := NewLineReader(strings.NewReader(input))
+	if got, err := l.Line(12345); err != nil || got != "line 12345: 世界" {
+		t.Errorf("Line(12345) = %q, %v", got, err)
+	}
+	if n, err := l.LineCount(); err != nil || n != 20000 {
+		t.Errorf("LineCount() = %d, %v, want 20000", n, err)
+	}
+	offset := int64(strings.Index(input, "line 15000:"))
+	if pos, err := l.Position(offset + 12); err != nil || pos != (Position{15000, 13}) {
+		t.Errorf("Position(%d) = %v, %v, want 15000:13", offset+12, pos, err)
+	}
+}
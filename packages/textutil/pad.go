@@ -0,0 +1,56 @@
+package textutil
+
+import "strings"
+
+// Align says which side of a column text sits on.
+type Align int
+
+const (
+	AlignLeft Align = iota
+	AlignRight
+	AlignCenter
+)
+
+// Pad pads s with spaces to width columns, aligned as given. Text already
+// as wide as width is returned unchanged. When centering leaves an odd
+// column over, it goes on the right.
+func Pad(s string, width int, align Align) string {
+	gap := width - Width(s)
+	if gap <= 0 {
+		return s
+	}
+
+	left := 0
+	switch align {
+	case AlignRight:
+		left = gap
+	case AlignCenter:
+		left = gap / 2
+	}
+
+	var b strings.Builder
+	b.Grow(len(s) + gap)
+	for i := 0; i < left; i++ {
+		b.WriteByte(' ')
+	}
+	b.WriteString(s)
+	for i := left; i < gap; i++ {
+		b.WriteByte(' ')
+	}
+	return b.String()
+}
+
+// PadLeft right-aligns s in width columns.
+func PadLeft(s string, width int) string {
+	return Pad(s, width, AlignRight)
+}
+
+// PadRight left-aligns s in width columns.
+func PadRight(s string, width int) string {
+	return Pad(s, width, AlignLeft)
+}
+
+// Center centers s in width columns.
+func Center(s string, width int) string {
+	return Pad(s, width, AlignCenter)
+}
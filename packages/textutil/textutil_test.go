@@ -0,0 +1,292 @@
+package textutil
+
+import (
+	"strings"
+	"testing"
+	"unicode"
+	"unicode/utf8"
+)
+
+func TestTruncate(t *testing.T) {
+	tests := []struct {
+		s        string
+		limit    int
+		ellipsis string
+		want     string
+	}{
+		{"Hello, world", 8, Ellipsis, "Hello, …"},
+		{"Hello", 5, Ellipsis, "Hello"},
+		{"Hello", 0, Ellipsis, ""},
+		{"Crème brûlée", 6, "...", "Crè..."},
+		{"日本語のテキスト", 4, Ellipsis, "日本語…"},
+		{"Hello", 2, "...", ".."},
+	}
+	for _, tt := range tests {
+		if got := Truncate(tt.s, tt.limit, tt.ellipsis); got != tt.want {
+			t.Errorf("Truncate(%q, %d, %q) = %q, want %q", tt.s, tt.limit, tt.ellipsis, got, tt.want)
+		}
+	}
+}
+
+func TestTruncateWidth(t *testing.T) {
+	tests := []struct {
+		s     string
+		limit int
+		want  string
+	}{
+		{"日本語のテキスト", 7, "日本語…"},
+		{"日本語のテキスト", 8, "日本語…"},
+		{"Hello, world", 6, "Hello…"},
+		{"Hello", 5, "Hello"},
+		{"日本", 1, "…"},
+	}
+	for _, tt := range tests {
+		if got := TruncateWidth(tt.s, tt.limit, Ellipsis); got != tt.want {
+			t.Errorf("TruncateWidth(%q, %d) = %q, want %q", tt.s, tt.limit, got, tt.want)
+		}
+	}
+}
+
+func TestWrap(t *testing.T) {
+	tests := []struct {
+		s     string
+		width int
+		want  string
+	}{
+		{"the quick brown fox jumps", 10, "the quick\nbrown fox\njumps"},
+		{"one  two\n\nthree", 20, "one two\n\nthree"},
+		{"a supercalifragilistic word", 5, "a\nsupercalifragilistic\nword"},
+		{"日本語 日本語", 6, "日本語\n日本語"},
+		{"unchanged", 0, "unchanged"},
+		{"\u200b and more", 8, "\u200b and\nmore"},
+	}
+	for _, tt := range tests {
+		if got := Wrap(tt.s, tt.width); got != tt.want {
+			t.Errorf("Wrap(%q, %d) = %q, want %q", tt.s, tt.width, got, tt.want)
+		}
+	}
+}
+
+func TestCase(t *testing.T) {
+	tests := []struct {
+		s                           string
+		snake, kebab, camel, pascal string
+	}{
+		{"parseHTTPRequest", "parse_http_request", "parse-http-request", "parseHttpRequest", "ParseHttpRequest"},
+		{"Parse HTTP request", "parse_http_request", "parse-http-request", "parseHttpRequest", "ParseHttpRequest"},
+		{"already_snake_case", "already_snake_case", "already-snake-case", "alreadySnakeCase", "AlreadySnakeCase"},
+		{"élan vital", "élan_vital", "élan-vital", "élanVital", "ÉlanVital"},
+		{"", "", "", "", ""},
+	}
+	for _, tt := range tests {
+		for _, c := range []struct {
+			name string
+			fn   func(string) string
+			want string
+		}{
+			{"SnakeCase", SnakeCase, tt.snake},
+			{"KebabCase", KebabCase, tt.kebab},
+			{"CamelCase", CamelCase, tt.camel},
+			{"PascalCase", PascalCase, tt.pascal},
+		} {
+			if got := c.fn(tt.s); got != c.want {
+				t.Errorf("%s(%q) = %q, want %q", c.name, tt.s, got, c.want)
+			}
+		}
+	}
+}
+
+func TestSlugify(t *testing.T) {
+	tests := []struct {
+		s, want string
+	}{
+		{"Crème Brûlée: A Recipe!", "creme-brulee-a-recipe"},
+		{"  --Hello,   World--  ", "hello-world"},
+		{"日本語", ""},
+		{"Go 1.22", "go-1-22"},
+	}
+	for _, tt := range tests {
+		if got := Slugify(tt.s); got != tt.want {
+			t.Errorf("Slugify(%q) = %q, want %q", tt.s, got, tt.want)
+		}
+	}
+}
+
+func TestPad(t *testing.T) {
+	tests := []struct {
+		s     string
+		width int
+		align Align
+		want  string
+	}{
+		{"ab", 5, AlignLeft, "ab   "},
+		{"ab", 5, AlignRight, "   ab"},
+		{"ab", 5, AlignCenter, " ab  "},
+		{"日本", 6, AlignCenter, " 日本 "},
+		{"toolong", 3, AlignRight, "toolong"},
+	}
+	for _, tt := range tests {
+		if got := Pad(tt.s, tt.width, tt.align); got != tt.want {
+			t.Errorf("Pad(%q, %d, %d) = %q, want %q", tt.s, tt.width, tt.align, got, tt.want)
+		}
+	}
+}
+
+func FuzzTruncate(f *testing.F) {
+	f.Add("Hello, world", 8, Ellipsis)
+	f.Add("日本語のテキスト", 4, "...")
+	f.Add("é", 1, "")
+	f.Fuzz(func(t *testing.T, s string, limit int, ellipsis string) {
+		got := Truncate(s, limit, ellipsis)
+		if n := utf8.RuneCountInString(got); n > max(limit, 0) {
+			t.Errorf("Truncate(%q, %d, %q) = %q, %d runes", s, limit, ellipsis, got, n)
+		}
+		if utf8.ValidString(s) && utf8.ValidString(ellipsis) && !utf8.ValidString(got) {
+			t.Errorf("Truncate(%q, %d, %q) = %q, which splits a character", s, limit, ellipsis, got)
+		}
+		if limit > 0 && utf8.RuneCountInString(s) <= limit && got != s {
+			t.Errorf("Truncate(%q, %d, %q) = %q, want it unchanged", s, limit, ellipsis, got)
+		}
+		if got != s && !strings.HasPrefix(ellipsis, got) &&
+			!(strings.HasSuffix(got, ellipsis) && strings.HasPrefix(s, strings.TrimSuffix(got, ellipsis))) {
+			t.Errorf("Truncate(%q, %d, %q) = %q, want a prefix ending in the ellipsis", s, limit, ellipsis, got)
+		}
+	})
+}
+
+func FuzzTruncateWidth(f *testing.F) {
+	f.Add("日本語のテキスト", 7, Ellipsis)
+	f.Add("Hello, world", 6, "...")
+	f.Fuzz(func(t *testing.T, s string, limit int, ellipsis string) {
+		got := TruncateWidth(s, limit, ellipsis)
+		if w := Width(got); w > max(limit, 0) {
+			t.Errorf("TruncateWidth(%q, %d, %q) = %q, %d columns", s, limit, ellipsis, got, w)
+		}
+	})
+}
+
+func FuzzWrap(f *testing.F) {
+	f.Add("the quick brown fox jumps", 10)
+	f.Add("日本語 日本語\n\nmore  words", 6)
+	f.Fuzz(func(t *testing.T, s string, width int) {
+		got := Wrap(s, width)
+		if width <= 0 {
+			if got != s {
+				t.Errorf("Wrap(%q, %d) = %q, want it unchanged", s, width, got)
+			}
+			return
+		}
+		// Wrap only moves the breaks between words, so the words themselves
+		// are unchanged.
+		if strings.Join(strings.Fields(got), " ") != strings.Join(strings.Fields(s), " ") {
+			t.Errorf("Wrap(%q, %d) = %q, which changes the words", s, width, got)
+		}
+		for _, line := range strings.Split(got, "\n") {
+			if Width(line) > width && len(strings.Fields(line)) > 1 {
+				t.Errorf("Wrap(%q, %d) has the line %q, %d columns wide", s, width, line, Width(line))
+			}
+		}
+	})
+}
+
+func FuzzWords(f *testing.F) {
+	f.Add("parseHTTPRequest")
+	f.Add("Crème Brûlée: A Recipe!")
+	f.Add("snake_case-and kebab")
+	f.Fuzz(func(t *testing.T, s string) {
+		var letters strings.Builder
+		for _, r := range s {
+			if unicode.IsLetter(r) || unicode.IsDigit(r) {
+				letters.WriteRune(r)
+			}
+		}
+		words := Words(s)
+		if got := strings.Join(words, ""); got != letters.String() {
+			t.Errorf("Words(%q) = %q, which loses or adds letters", s, words)
+		}
+		if KebabCase(s) != strings.ReplaceAll(SnakeCase(s), "_", "-") {
+			t.Errorf("KebabCase(%q) = %q and SnakeCase %q split differently", s, KebabCase(s), SnakeCase(s))
+		}
+	})
+}
+
+func FuzzSlugify(f *testing.F) {
+	f.Add("Crème Brûlée: A Recipe!")
+	f.Add("--already-a-slug--")
+	f.Fuzz(func(t *testing.T, s string) {
+		got := Slugify(s)
+		for _, r := range got {
+			if !(r >= 'a' && r <= 'z' || r >= '0' && r <= '9' || r == '-') {
+				t.Fatalf("Slugify(%q) = %q, which has %q", s, got, r)
+			}
+		}
+		if strings.HasPrefix(got, "-") || strings.HasSuffix(got, "-") || strings.Contains(got, "--") {
+			t.Errorf("Slugify(%q) = %q, with a stray hyphen", s, got)
+		}
+		if again := Slugify(got); again != got {
+			t.Errorf("Slugify(%q) = %q, but slugifying that gives %q", s, got, again)
+		}
+	})
+}
+
+func FuzzPad(f *testing.F) {
+	f.Add("ab", 5, int(AlignCenter))
+	f.Add("日本", 6, int(AlignRight))
+	f.Fuzz(func(t *testing.T, s string, width, align int) {
+		if width > 1<<16 {
+			return
+		}
+		got := Pad(s, width, Align(align))
+		if w := Width(got); w != max(width, Width(s)) {
+			t.Errorf("Pad(%q, %d, %d) = %q, %d columns wide", s, width, align, got, w)
+		}
+		if strings.TrimSpace(got) != strings.TrimSpace(s) {
+			t.Errorf("Pad(%q, %d, %d) = %q, which changes the text", s, width, align, got)
+		}
+	})
+}
+
+// text is a paragraph of mixed Latin and East Asian text for the benchmarks.
+var text = strings.Repeat("The quick brown fox jumps over the lazy dog. 日本語のテキストも混ざっています。 Crème brûlée! ", 20)
+
+func BenchmarkTruncate(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		Truncate(text, 200, Ellipsis)
+	}
+}
+
+func BenchmarkTruncateWidth(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		TruncateWidth(text, 200, Ellipsis)
+	}
+}
+
+func BenchmarkWrap(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		Wrap(text, 72)
+	}
+}
+
+func BenchmarkSnakeCase(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		SnakeCase("parseHTTPRequestFromTheServerResponseBody")
+	}
+}
+
+func BenchmarkCamelCase(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		CamelCase("parse_http_request_from_the_server_response_body")
+	}
+}
+
+func BenchmarkSlugify(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		Slugify("Crème Brûlée: A Recipe for the Perfect Caramelised Top!")
+	}
+}
+
+func BenchmarkPad(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		Pad("日本語", 40, AlignCenter)
+	}
+}
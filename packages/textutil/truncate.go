@@ -0,0 +1,80 @@
+package textutil
+
+import (
+	"strings"
+	"unicode/utf8"
+)
+
+// Ellipsis is the usual marker to pass to Truncate and TruncateWidth.
+const Ellipsis = "…"
+
+// Truncate shortens s to at most limit runes, ending it with ellipsis when
+// anything was cut off. It never splits a multi-byte character. If ellipsis
+// is longer than limit, as much of it as fits is returned.
+func Truncate(s string, limit int, ellipsis string) string {
+	if limit <= 0 {
+		return ""
+	}
+	if utf8.RuneCountInString(s) <= limit {
+		return s
+	}
+
+	keep := limit - utf8.RuneCountInString(ellipsis)
+	if keep <= 0 {
+		return prefix(ellipsis, limit)
+	}
+
+	head := prefix(s, keep)
+	var b strings.Builder
+	b.Grow(len(head) + len(ellipsis))
+	b.WriteString(head)
+	b.WriteString(ellipsis)
+	return b.String()
+}
+
+// TruncateWidth is Truncate measured in terminal columns instead of runes,
+// for text that has to fit a column.
+func TruncateWidth(s string, limit int, ellipsis string) string {
+	if limit <= 0 {
+		return ""
+	}
+	if Width(s) <= limit {
+		return s
+	}
+
+	keep := limit - Width(ellipsis)
+	if keep < 0 {
+		return TruncateWidth(ellipsis, limit, "")
+	}
+
+	var b strings.Builder
+	b.Grow(len(s) + len(ellipsis))
+	w := 0
+	for _, r := range s {
+		rw := RuneWidth(r)
+		if w+rw > keep {
+			break
+		}
+		w += rw
+		b.WriteRune(r)
+	}
+	b.WriteString(ellipsis)
+	return b.String()
+}
+
+// prefix returns the first n runes of s.
+func prefix(s string, n int) string {
+	return s[:runeOffset(s, n)]
+}
+
+// runeOffset returns the byte offset of the nth rune of s, or len(s) if s is
+// shorter.
+func runeOffset(s string, n int) int {
+	for i := range s {
+		if n == 0 {
+			return i
+		}
+		n--
+	}
+	return len(s)
+}
@@ -0,0 +1,39 @@
+// Package textutil holds string helpers built on strings.Builder: rune-safe
+// truncation, word wrapping, case conversion, slugs and padding.
+//
+// Functions that lay text out in columns measure it by display width rather
+// than bytes or runes, so East Asian wide characters count as two columns
+// and combining marks as none.
+package textutil
+
+import (
+	"unicode"
+	"unicode/utf8"
+
+	"golang.org/x/text/width"
+)
+
+// RuneWidth returns the number of terminal columns r takes up.
+func RuneWidth(r rune) int {
+	switch {
+	case r == utf8.RuneError, unicode.Is(unicode.Mn, r), unicode.Is(unicode.Me, r), unicode.Is(unicode.Cf, r):
+		return 0
+	case unicode.IsControl(r):
+		return 0
+	}
+
+	switch width.LookupRune(r).Kind() {
+	case width.EastAsianWide, width.EastAsianFullwidth:
+		return 2
+	}
+	return 1
+}
+
+// Width returns the number of terminal columns s takes up.
+func Width(s string) int {
+	w := 0
+	for _, r := range s {
+		w += RuneWidth(r)
+	}
+	return w
+}
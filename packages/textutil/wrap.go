@@ -0,0 +1,41 @@
+package textutil
+
+import "strings"
+
+// Wrap breaks s into lines no wider than width columns, breaking between
+// words. Existing line breaks are kept, and runs of spaces between words
+// are collapsed to one. A word wider than width is put on a line of its own
+// rather than split.
+func Wrap(s string, width int) string {
+	if width <= 0 {
+		return s
+	}
+
+	var b strings.Builder
+	b.Grow(len(s) + len(s)/width)
+
+	for i, line := range strings.Split(s, "\n") {
+		if i > 0 {
+			b.WriteByte('\n')
+		}
+
+		// Words can be zero columns wide, so col alone does not say whether
+		// the line has any yet.
+		col := 0
+		for j, word := range strings.Fields(line) {
+			w := Width(word)
+			switch {
+			case j == 0:
+			case col+1+w > width:
+				b.WriteByte('\n')
+				col = 0
+			default:
+				b.WriteByte(' ')
+				col++
+			}
+			b.WriteString(word)
+			col += w
+		}
+	}
+	return b.String()
+}